@@ -0,0 +1,258 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"base58"
+	"bech32"
+	"chaincfg"
+	"ecc"
+	"hashes"
+)
+
+// Addresses of the standard output types. Base58Check addresses are a
+// version byte followed by a HASH160, segwit addresses are a witness
+// version and program encoded with bech32 or bech32m
+
+type Type int
+
+const (
+	P2PKH Type = iota + 1
+	P2SH
+	P2WPKH
+	P2WSH
+	P2TR
+	WitnessUnknown
+)
+
+const (
+	opDup         = 0x76
+	opHash160     = 0xa9
+	opEqual       = 0x87
+	opEqualVerify = 0x88
+	opCheckSig    = 0xac
+	op1           = 0x51
+
+	hash160Size = hashes.Hash160Size
+)
+
+var (
+	ErrInvalidAddress = errors.New("invalid address")
+	ErrUnknownNetwork = errors.New("address of an unknown network")
+)
+
+// Address is a decoded or derived address, ScriptPubKey is the output
+// script paying to it
+type Address interface {
+	fmt.Stringer
+	Type() Type
+	Network() *chaincfg.Params
+	ScriptPubKey() []byte
+}
+
+func (t Type) String() string {
+	switch t {
+	case P2PKH:
+		return "p2pkh"
+	case P2SH:
+		return "p2sh"
+	case P2WPKH:
+		return "p2wpkh"
+	case P2WSH:
+		return "p2wsh"
+	case P2TR:
+		return "p2tr"
+	case WitnessUnknown:
+		return "witness_unknown"
+	default:
+		return fmt.Sprintf("Type(%d)", int(t))
+	}
+}
+
+// PubKeyHashAddress pays to the HASH160 of a SEC encoded public key
+type PubKeyHashAddress struct {
+	Hash [hash160Size]byte
+	net  *chaincfg.Params
+}
+
+// ScriptHashAddress pays to the HASH160 of a redeem script
+type ScriptHashAddress struct {
+	Hash [hash160Size]byte
+	net  *chaincfg.Params
+}
+
+// WitnessAddress pays to a witness program, its type depends on the
+// version and the length of the program
+type WitnessAddress struct {
+	Version byte
+	Program []byte
+	net     *chaincfg.Params
+}
+
+// Addresses are the standard single key addresses of a public key
+type Addresses struct {
+	P2PKH             *PubKeyHashAddress
+	P2PKHUncompressed *PubKeyHashAddress
+	P2SHP2WPKH        *ScriptHashAddress
+	P2WPKH            *WitnessAddress
+	P2TR              *WitnessAddress
+}
+
+// FromPoint returns every standard address of p on net, P2PKH of both
+// its compressed and uncompressed encodings
+func FromPoint(p *ecc.Point, net *chaincfg.Params) Addresses {
+	return Addresses{
+		P2PKH:             NewP2PKH(p, true, net),
+		P2PKHUncompressed: NewP2PKH(p, false, net),
+		P2SHP2WPKH:        NewP2SHP2WPKH(p, net),
+		P2WPKH:            NewP2WPKH(p, net),
+		P2TR:              NewP2TR(p, net),
+	}
+}
+
+// NewP2PKH returns the pay to public key hash address of p
+func NewP2PKH(p *ecc.Point, compressed bool, net *chaincfg.Params) *PubKeyHashAddress {
+	return &PubKeyHashAddress{Hash: hashes.Hash160(sec(p, compressed)), net: net}
+}
+
+// NewP2SHP2WPKH returns the P2WPKH output of p nested in a P2SH one
+func NewP2SHP2WPKH(p *ecc.Point, net *chaincfg.Params) *ScriptHashAddress {
+	redeemScript := NewP2WPKH(p, net).ScriptPubKey()
+	return &ScriptHashAddress{Hash: hashes.Hash160(redeemScript), net: net}
+}
+
+// NewP2WPKH returns the version 0 witness address of the compressed p
+func NewP2WPKH(p *ecc.Point, net *chaincfg.Params) *WitnessAddress {
+	h := hashes.Hash160(sec(p, true))
+	return &WitnessAddress{Version: 0, Program: h[:], net: net}
+}
+
+// NewP2TR returns the taproot address of p spendable by key path only,
+// the output key commits to no script tree as recommended by BIP86
+func NewP2TR(p *ecc.Point, net *chaincfg.Params) *WitnessAddress {
+	return &WitnessAddress{Version: 1, Program: TaprootOutputKey(p, nil).XBytes(), net: net}
+}
+
+func (a *PubKeyHashAddress) Type() Type                { return P2PKH }
+func (a *PubKeyHashAddress) Network() *chaincfg.Params { return a.net }
+
+func (a *PubKeyHashAddress) String() string {
+	return base58.CheckEncode(append([]byte{a.net.PubKeyHashAddrID}, a.Hash[:]...))
+}
+
+// ScriptPubKey returns OP_DUP OP_HASH160 <hash> OP_EQUALVERIFY OP_CHECKSIG
+func (a *PubKeyHashAddress) ScriptPubKey() []byte {
+	script := append([]byte{opDup, opHash160, hash160Size}, a.Hash[:]...)
+	return append(script, opEqualVerify, opCheckSig)
+}
+
+func (a *ScriptHashAddress) Type() Type                { return P2SH }
+func (a *ScriptHashAddress) Network() *chaincfg.Params { return a.net }
+
+func (a *ScriptHashAddress) String() string {
+	return base58.CheckEncode(append([]byte{a.net.ScriptHashAddrID}, a.Hash[:]...))
+}
+
+// ScriptPubKey returns OP_HASH160 <hash> OP_EQUAL
+func (a *ScriptHashAddress) ScriptPubKey() []byte {
+	script := append([]byte{opHash160, hash160Size}, a.Hash[:]...)
+	return append(script, opEqual)
+}
+
+func (a *WitnessAddress) Type() Type {
+	switch {
+	case a.Version == 0 && len(a.Program) == 20:
+		return P2WPKH
+	case a.Version == 0 && len(a.Program) == 32:
+		return P2WSH
+	case a.Version == 1 && len(a.Program) == 32:
+		return P2TR
+	default:
+		return WitnessUnknown
+	}
+}
+
+func (a *WitnessAddress) Network() *chaincfg.Params { return a.net }
+
+func (a *WitnessAddress) String() string {
+	addr, err := bech32.EncodeSegwitAddress(a.net.Bech32HRP, a.Version, a.Program)
+	if err != nil {
+		panic(err)
+	}
+
+	return addr
+}
+
+// ScriptPubKey returns OP_n <program>
+func (a *WitnessAddress) ScriptPubKey() []byte {
+	op := a.Version
+	if op > 0 {
+		op += op1 - 1
+	}
+
+	return append([]byte{op, byte(len(a.Program))}, a.Program...)
+}
+
+// DecodeAddress parses a base58 or segwit address of a registered
+// network, the first network using the prefix of addr is reported
+func DecodeAddress(addr string) (Address, error) {
+	lower := strings.ToLower(addr)
+	if sep := strings.LastIndexByte(lower, '1'); sep > 0 {
+		for _, net := range chaincfg.Networks() {
+			if lower[:sep] == net.Bech32HRP {
+				return decodeSegwit(addr, net)
+			}
+		}
+	}
+
+	decoded, err := base58.CheckDecode(addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+
+	if len(decoded) != 1+hash160Size {
+		return nil, fmt.Errorf("%w: unexpected %d bytes payload", ErrInvalidAddress, len(decoded))
+	}
+
+	var h [hash160Size]byte
+	copy(h[:], decoded[1:])
+
+	for _, net := range chaincfg.Networks() {
+		switch decoded[0] {
+		case net.PubKeyHashAddrID:
+			return &PubKeyHashAddress{Hash: h, net: net}, nil
+		case net.ScriptHashAddrID:
+			return &ScriptHashAddress{Hash: h, net: net}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: version byte %#02x", ErrUnknownNetwork, decoded[0])
+}
+
+func decodeSegwit(addr string, net *chaincfg.Params) (Address, error) {
+	version, program, err := bech32.DecodeSegwitAddress(net.Bech32HRP, addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+
+	return &WitnessAddress{Version: version, Program: program, net: net}, nil
+}
+
+func sec(p *ecc.Point, compressed bool) []byte {
+	if p.IsInfinity() {
+		panic("the point at infinity has no address")
+	}
+
+	if !compressed {
+		return append(append([]byte{0x04}, p.XBytes()...), p.YBytes()...)
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
@@ -0,0 +1,106 @@
+package address_test
+
+import (
+	"address"
+	"base58"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"chaincfg"
+	"ecc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressesOfPoint(t *testing.T) {
+	// public key of the private key 1
+	p := ecc.NewPrivateKey(big.NewInt(1)).PublicKey()
+
+	vectors := []struct {
+		addr   address.Address
+		typ    address.Type
+		str    string
+		script string
+	}{
+		{address.NewP2PKH(p, true, &chaincfg.MainNetParams), address.P2PKH, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac"},
+		{address.NewP2PKH(p, false, &chaincfg.MainNetParams), address.P2PKH, "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm", "76a91491b24bf9f5288532960ac687abb035127b1d28a588ac"},
+		{address.NewP2SHP2WPKH(p, &chaincfg.MainNetParams), address.P2SH, "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN", "a914bcfeb728b584253d5f3f70bcb780e9ef218a68f487"},
+		{address.NewP2WPKH(p, &chaincfg.MainNetParams), address.P2WPKH, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{address.NewP2PKH(p, true, &chaincfg.TestNet3Params), address.P2PKH, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac"},
+		{address.NewP2WPKH(p, &chaincfg.RegressionNetParams), address.P2WPKH, "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080", "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+	}
+
+	for _, v := range vectors {
+		require.Equal(t, v.typ, v.addr.Type(), v.str)
+		require.Equal(t, v.str, v.addr.String())
+		require.Equal(t, v.script, hex.EncodeToString(v.addr.ScriptPubKey()), v.str)
+
+		decoded, err := address.DecodeAddress(v.str)
+		require.NoError(t, err, v.str)
+		require.Equal(t, v.typ, decoded.Type(), v.str)
+		require.Equal(t, v.str, decoded.String())
+		require.Equal(t, v.script, hex.EncodeToString(decoded.ScriptPubKey()), v.str)
+	}
+}
+
+func TestFromPoint(t *testing.T) {
+	p := ecc.NewPrivateKey(big.NewInt(1)).PublicKey()
+
+	addrs := address.FromPoint(p, &chaincfg.MainNetParams)
+	require.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", addrs.P2PKH.String())
+	require.Equal(t, "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm", addrs.P2PKHUncompressed.String())
+	require.Equal(t, "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN", addrs.P2SHP2WPKH.String())
+	require.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", addrs.P2WPKH.String())
+	require.Equal(t, address.NewP2TR(p, &chaincfg.MainNetParams).String(), addrs.P2TR.String())
+	require.Equal(t, address.P2TR, addrs.P2TR.Type())
+
+	addrs = address.FromPoint(p, &chaincfg.TestNet3Params)
+	require.Equal(t, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", addrs.P2PKH.String())
+	require.Same(t, &chaincfg.TestNet3Params, addrs.P2TR.Network())
+}
+
+func TestDecodeAddress(t *testing.T) {
+	vectors := []struct {
+		str    string
+		typ    address.Type
+		net    *chaincfg.Params
+		script string
+	}{
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", address.P2PKH, &chaincfg.MainNetParams, "76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac"},
+		{"3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN", address.P2SH, &chaincfg.MainNetParams, "a914bcfeb728b584253d5f3f70bcb780e9ef218a68f487"},
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", address.P2WPKH, &chaincfg.MainNetParams, "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", address.P2WSH, &chaincfg.TestNet3Params, "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", address.P2TR, &chaincfg.MainNetParams, "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", address.WitnessUnknown, &chaincfg.MainNetParams, "5210751e76e8199196d454941c45d1b3a323"},
+	}
+
+	for _, v := range vectors {
+		decoded, err := address.DecodeAddress(v.str)
+		require.NoError(t, err, v.str)
+		require.Equal(t, v.typ, decoded.Type(), v.str)
+		require.Same(t, v.net, decoded.Network(), v.str)
+		require.Equal(t, v.script, hex.EncodeToString(decoded.ScriptPubKey()), v.str)
+	}
+
+	invalid := map[string]error{
+		"":                                   address.ErrInvalidAddress,
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb": address.ErrInvalidAddress,
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5": address.ErrInvalidAddress,
+		"tc1qw508d6qejxtdg4y5r3zarvary0c5xw7kg3g4ty": address.ErrInvalidAddress,
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh": address.ErrInvalidAddress,
+		"1Wh4bh": address.ErrInvalidAddress,
+
+		// litecoin version byte
+		base58.CheckEncode(append([]byte{0x30}, make([]byte, 20)...)): address.ErrUnknownNetwork,
+
+		// valid checksums around payloads of the wrong size
+		base58.CheckEncode(make([]byte, 20)): address.ErrInvalidAddress,
+		base58.CheckEncode(make([]byte, 22)): address.ErrInvalidAddress,
+	}
+
+	for s, expected := range invalid {
+		_, err := address.DecodeAddress(s)
+		require.ErrorIs(t, err, expected, s)
+	}
+}
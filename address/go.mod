@@ -0,0 +1,31 @@
+module address
+
+go 1.23.1
+
+replace base58 => ../base58
+
+replace bech32 => ../bech32
+
+replace chaincfg => ../chaincfg
+
+replace ecc => ../ecc
+
+replace hashes => ../hashes
+
+replace wire => ../wire
+
+require (
+	base58 v0.0.0-00010101000000-000000000000
+	bech32 v0.0.0-00010101000000-000000000000
+	chaincfg v0.0.0-00010101000000-000000000000
+	ecc v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+	hashes v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	wire v0.0.0-00010101000000-000000000000 // indirect
+)
@@ -0,0 +1,35 @@
+package address
+
+import (
+	"bytes"
+	"math/big"
+
+	"ecc"
+	"hashes"
+)
+
+const tapTweakTag = "TapTweak"
+
+// TaprootOutputKey tweaks the internal key p as specified by BIP341,
+// Q = P + int(hashTapTweak(bytes(P) || merkleRoot)) * G where P is the
+// point with the x coordinate of p and an even y. A nil merkleRoot
+// commits to no script tree
+func TaprootOutputKey(p *ecc.Point, merkleRoot []byte) *ecc.Point {
+	internal, err := ecc.FromSec(bytes.NewReader(append([]byte{0x02}, p.XBytes()...)))
+	if err != nil {
+		panic(err)
+	}
+
+	h := hashes.TaggedHash(tapTweakTag, internal.XBytes(), merkleRoot)
+	t := big.NewInt(0).SetBytes(h[:])
+	if t.Cmp(ecc.BitcoinN) >= 0 {
+		panic("taproot tweak is not lower than the curve order")
+	}
+
+	q := internal.Add(ecc.BitcoingGenPoint.ScalarMul(t))
+	if q.IsInfinity() {
+		panic("taproot output key is the point at infinity")
+	}
+
+	return q
+}
@@ -0,0 +1,64 @@
+package address_test
+
+import (
+	"address"
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"chaincfg"
+	"ecc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func xOnlyPoint(t *testing.T, x string) *ecc.Point {
+	sec, err := hex.DecodeString("02" + x)
+	require.NoError(t, err)
+
+	p, err := ecc.FromSec(bytes.NewReader(sec))
+	require.NoError(t, err)
+	return p
+}
+
+func TestTaprootOutputKey(t *testing.T) {
+	vectors := []struct {
+		internal string
+		output   string
+		address  string
+	}{
+		// BIP86 first receiving address of the test mnemonic
+		{
+			"cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+			"a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+			"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+		},
+		// BIP341 wallet vector without scripts
+		{
+			"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			"53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+			"bc1p2wsldez5mud2yam29q22wgfh9439spgduvct83k3pm50fcxa5dps59h4z5",
+		},
+	}
+
+	for _, v := range vectors {
+		p := xOnlyPoint(t, v.internal)
+		require.Equal(t, v.output, hex.EncodeToString(address.TaprootOutputKey(p, nil).XBytes()))
+
+		addr := address.NewP2TR(p, &chaincfg.MainNetParams)
+		require.Equal(t, address.P2TR, addr.Type())
+		require.Equal(t, v.address, addr.String())
+
+		// the odd y point with the same x has the same address
+		require.Equal(t, v.address, address.NewP2TR(p.Neg(), &chaincfg.MainNetParams).String())
+	}
+}
+
+func TestTaprootOutputKeyWithScripts(t *testing.T) {
+	// BIP341 wallet vector with a single leaf
+	p := xOnlyPoint(t, "187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27")
+	merkleRoot, err := hex.DecodeString("5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21")
+	require.NoError(t, err)
+
+	require.Equal(t, "147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3", hex.EncodeToString(address.TaprootOutputKey(p, merkleRoot).XBytes()))
+}
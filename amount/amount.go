@@ -0,0 +1,224 @@
+package amount
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Amounts of bitcoin as an integer number of satoshis, decimal strings
+// are parsed and formatted exactly and never go through floating point
+
+type Amount int64
+
+const (
+	SatoshiPerBitcoin Amount = 100_000_000
+
+	// MaxMoney is the largest amount a transaction output may hold, the
+	// MAX_MONEY sanity bound of Bitcoin Core
+	MaxMoney = 21_000_000 * SatoshiPerBitcoin
+)
+
+// Unit is a denomination of bitcoin, its value is the number of decimals
+// of an amount in that unit
+type Unit int
+
+const (
+	UnitSatoshi  Unit = 0
+	UnitMicroBTC Unit = 2
+	UnitMilliBTC Unit = 5
+	UnitBTC      Unit = 8
+)
+
+var (
+	ErrInvalidAmount = errors.New("invalid amount")
+	ErrTooPrecise    = errors.New("amount more precise than its unit")
+	ErrOutOfRange    = errors.New("amount out of range")
+	ErrUnknownUnit   = errors.New("unknown amount unit")
+	ErrOverflow      = errors.New("amount overflow")
+)
+
+// unitSymbols maps the accepted spellings of the units, String gives the
+// canonical one
+var unitSymbols = map[string]Unit{
+	"BTC":  UnitBTC,
+	"mBTC": UnitMilliBTC,
+	"µBTC": UnitMicroBTC,
+	"μBTC": UnitMicroBTC,
+	"uBTC": UnitMicroBTC,
+	"sat":  UnitSatoshi,
+}
+
+func (u Unit) String() string {
+	switch u {
+	case UnitBTC:
+		return "BTC"
+	case UnitMilliBTC:
+		return "mBTC"
+	case UnitMicroBTC:
+		return "µBTC"
+	case UnitSatoshi:
+		return "sat"
+	default:
+		return fmt.Sprintf("Unit(%d)", int(u))
+	}
+}
+
+func (u Unit) valid() bool {
+	return u == UnitBTC || u == UnitMilliBTC || u == UnitMicroBTC || u == UnitSatoshi
+}
+
+// ParseAmount parses a decimal number followed by its unit, separated by
+// at most one space, as in "0.5 BTC" or "1500sat". The number may not
+// have more decimals than the unit and must be within MaxMoney
+func ParseAmount(s string) (Amount, error) {
+	number, symbol := splitUnit(s)
+
+	u, ok := unitSymbols[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, s)
+	}
+
+	return ParseAmountUnit(number, u)
+}
+
+// ParseAmountUnit parses a decimal number of unit u without a suffix
+func ParseAmountUnit(s string, u Unit) (Amount, error) {
+	if !u.valid() {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownUnit, u)
+	}
+
+	v, err := parseFixedPoint(s, int(u), false)
+	if err != nil {
+		return 0, err
+	}
+
+	a := Amount(v)
+	if a < -MaxMoney || a > MaxMoney {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+	}
+
+	return a, nil
+}
+
+// Format writes a in unit u with its symbol, trailing zero decimals are
+// omitted
+func (a Amount) Format(u Unit) string {
+	return a.FormatNumber(u) + " " + u.String()
+}
+
+// FormatNumber writes a in unit u without the unit symbol
+func (a Amount) FormatNumber(u Unit) string {
+	if !u.valid() {
+		panic(fmt.Sprintf("unknown unit %s", u))
+	}
+
+	return formatFixedPoint(int64(a), int(u), true)
+}
+
+func (a Amount) String() string {
+	return a.Format(UnitBTC)
+}
+
+// InMoneyRange reports whether a is a valid output value, between zero
+// and MaxMoney inclusive
+func (a Amount) InMoneyRange() bool {
+	return a >= 0 && a <= MaxMoney
+}
+
+func (a Amount) Add(b Amount) (Amount, error) {
+	c := a + b
+	if (c > a) != (b > 0) {
+		return 0, fmt.Errorf("%w: %d + %d", ErrOverflow, a, b)
+	}
+
+	return c, nil
+}
+
+func (a Amount) Sub(b Amount) (Amount, error) {
+	c := a - b
+	if (c < a) != (b > 0) {
+		return 0, fmt.Errorf("%w: %d - %d", ErrOverflow, a, b)
+	}
+
+	return c, nil
+}
+
+func (a Amount) MulInt(n int64) (Amount, error) {
+	if a == 0 || n == 0 {
+		return 0, nil
+	}
+
+	c := a * Amount(n)
+	if (a == -1 && n == math.MinInt64) || (n == -1 && a == math.MinInt64) || c/Amount(n) != a {
+		return 0, fmt.Errorf("%w: %d * %d", ErrOverflow, a, n)
+	}
+
+	return c, nil
+}
+
+// Sum adds amounts, each of them and every partial sum must be in money
+// range as Bitcoin Core requires of transaction outputs
+func Sum(amounts ...Amount) (Amount, error) {
+	var total Amount
+	for _, a := range amounts {
+		if !a.InMoneyRange() {
+			return 0, fmt.Errorf("%w: %d", ErrOutOfRange, a)
+		}
+
+		total += a
+		if !total.InMoneyRange() {
+			return 0, fmt.Errorf("%w: sum exceeds MaxMoney", ErrOutOfRange)
+		}
+	}
+
+	return total, nil
+}
+
+// MarshalJSON encodes a as a number of bitcoins with 8 decimals, the way
+// Bitcoin Core's RPC returns amounts
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(formatFixedPoint(int64(a), int(UnitBTC), false)), nil
+}
+
+// UnmarshalJSON accepts a number of bitcoins or a string holding one,
+// with the same rules as Bitcoin Core's RPC: an exponent is allowed, at
+// most 8 decimals and the value must be in money range
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	v, err := unmarshalBTC(data)
+	if err != nil {
+		return err
+	}
+
+	if !Amount(v).InMoneyRange() {
+		return fmt.Errorf("%w: %s", ErrOutOfRange, data)
+	}
+
+	*a = Amount(v)
+	return nil
+}
+
+// unmarshalBTC decodes a JSON number or string of bitcoins to satoshis
+func unmarshalBTC(data []byte) (int64, error) {
+	number := string(data)
+	if strings.HasPrefix(number, `"`) {
+		if err := json.Unmarshal(data, &number); err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+		}
+	}
+
+	return parseFixedPoint(number, int(UnitBTC), true)
+}
+
+// splitUnit cuts s after its last digit, a single space may separate the
+// number from the unit symbol
+func splitUnit(s string) (string, string) {
+	i := strings.LastIndexAny(s, "0123456789") + 1
+	return s[:i], strings.TrimPrefix(s[i:], " ")
+}
@@ -0,0 +1,208 @@
+package amount_test
+
+import (
+	"amount"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	valid := map[string]amount.Amount{
+		"1 BTC":                amount.SatoshiPerBitcoin,
+		"0.5 BTC":              50_000_000,
+		"0.00000001BTC":        1,
+		"-1.5 BTC":             -150_000_000,
+		"21000000 BTC":         amount.MaxMoney,
+		"1.23456 mBTC":         123_456,
+		"0 mBTC":               0,
+		"1.5 µBTC":             150,
+		"1.5 μBTC":             150,
+		"7 uBTC":               700,
+		"1500sat":              1500,
+		"2100000000000000 sat": amount.MaxMoney,
+	}
+
+	for s, expected := range valid {
+		a, err := amount.ParseAmount(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, a, s)
+	}
+
+	invalid := map[string]error{
+		"1":                        amount.ErrUnknownUnit,
+		"1 btc":                    amount.ErrUnknownUnit,
+		"1  BTC":                   amount.ErrUnknownUnit,
+		"1 BTC ":                   amount.ErrUnknownUnit,
+		"BTC":                      amount.ErrInvalidAmount,
+		" 1 BTC":                   amount.ErrInvalidAmount,
+		"+1 BTC":                   amount.ErrInvalidAmount,
+		"01 BTC":                   amount.ErrInvalidAmount,
+		".5 BTC":                   amount.ErrInvalidAmount,
+		"1. BTC":                   amount.ErrUnknownUnit,
+		"1e8 sat":                  amount.ErrInvalidAmount,
+		"1,5 BTC":                  amount.ErrInvalidAmount,
+		"0.000000001 BTC":          amount.ErrTooPrecise,
+		"0.5 sat":                  amount.ErrTooPrecise,
+		"1.234567 mBTC":            amount.ErrTooPrecise,
+		"21000000.00000001 BTC":    amount.ErrOutOfRange,
+		"-21000001 BTC":            amount.ErrOutOfRange,
+		"99999999999999999999 sat": amount.ErrOutOfRange,
+	}
+
+	for s, expected := range invalid {
+		_, err := amount.ParseAmount(s)
+		require.ErrorIs(t, err, expected, s)
+	}
+
+	// trailing zeros beyond the unit precision are exact
+	a, err := amount.ParseAmountUnit("1.5000000000", amount.UnitBTC)
+	require.NoError(t, err)
+	require.Equal(t, amount.Amount(150_000_000), a)
+
+	_, err = amount.ParseAmountUnit("1", amount.Unit(3))
+	require.ErrorIs(t, err, amount.ErrUnknownUnit)
+}
+
+func TestFormatAmount(t *testing.T) {
+	a := amount.Amount(123_456_789)
+	require.Equal(t, "1.23456789 BTC", a.String())
+	require.Equal(t, "1234.56789 mBTC", a.Format(amount.UnitMilliBTC))
+	require.Equal(t, "1234567.89 µBTC", a.Format(amount.UnitMicroBTC))
+	require.Equal(t, "123456789 sat", a.Format(amount.UnitSatoshi))
+
+	require.Equal(t, "1 BTC", amount.SatoshiPerBitcoin.String())
+	require.Equal(t, "0 BTC", amount.Amount(0).String())
+	require.Equal(t, "-0.00000001 BTC", amount.Amount(-1).String())
+	require.Equal(t, "0.1", amount.Amount(10_000_000).FormatNumber(amount.UnitBTC))
+	require.Equal(t, "-92233720368.54775808 BTC", amount.Amount(math.MinInt64).String())
+
+	require.Panics(t, func() { a.Format(amount.Unit(1)) })
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a, err := amount.Amount(5).Add(7)
+	require.NoError(t, err)
+	require.Equal(t, amount.Amount(12), a)
+
+	a, err = amount.Amount(5).Sub(7)
+	require.NoError(t, err)
+	require.Equal(t, amount.Amount(-2), a)
+
+	a, err = amount.SatoshiPerBitcoin.MulInt(-3)
+	require.NoError(t, err)
+	require.Equal(t, amount.Amount(-300_000_000), a)
+
+	_, err = amount.Amount(math.MaxInt64).Add(1)
+	require.ErrorIs(t, err, amount.ErrOverflow)
+	_, err = amount.Amount(math.MinInt64).Add(-1)
+	require.ErrorIs(t, err, amount.ErrOverflow)
+	_, err = amount.Amount(math.MinInt64).Sub(1)
+	require.ErrorIs(t, err, amount.ErrOverflow)
+	_, err = amount.Amount(0).Sub(math.MinInt64)
+	require.ErrorIs(t, err, amount.ErrOverflow)
+	_, err = amount.MaxMoney.MulInt(5000)
+	require.ErrorIs(t, err, amount.ErrOverflow)
+	_, err = amount.Amount(-1).MulInt(math.MinInt64)
+	require.ErrorIs(t, err, amount.ErrOverflow)
+
+	require.True(t, amount.MaxMoney.InMoneyRange())
+	require.True(t, amount.Amount(0).InMoneyRange())
+	require.False(t, (amount.MaxMoney + 1).InMoneyRange())
+	require.False(t, amount.Amount(-1).InMoneyRange())
+
+	sum, err := amount.Sum(1, 2, amount.SatoshiPerBitcoin)
+	require.NoError(t, err)
+	require.Equal(t, amount.Amount(100_000_003), sum)
+
+	_, err = amount.Sum(amount.MaxMoney, 1)
+	require.ErrorIs(t, err, amount.ErrOutOfRange)
+	_, err = amount.Sum(5, -1)
+	require.ErrorIs(t, err, amount.ErrOutOfRange)
+}
+
+func TestAmountJSON(t *testing.T) {
+	type output struct {
+		Value amount.Amount `json:"value"`
+	}
+
+	encoded, err := json.Marshal(output{Value: 150_000_000})
+	require.NoError(t, err)
+	require.Equal(t, `{"value":1.50000000}`, string(encoded))
+
+	encoded, err = json.Marshal(output{Value: -1})
+	require.NoError(t, err)
+	require.Equal(t, `{"value":-0.00000001}`, string(encoded))
+
+	// numbers Bitcoin Core's AmountFromValue accepts
+	valid := map[string]amount.Amount{
+		`0`:                 0,
+		`0.00000001`:        1,
+		`1.5`:               150_000_000,
+		`"1.5"`:             150_000_000,
+		`1e-8`:              1,
+		`1E+2`:              100 * amount.SatoshiPerBitcoin,
+		`0.0000001e-1`:      1,
+		`12345678.90000000`: 1_234_567_890_000_000,
+		`21000000`:          amount.MaxMoney,
+		`0.000000010`:       1,
+	}
+
+	for s, expected := range valid {
+		var out output
+		require.NoError(t, json.Unmarshal([]byte(`{"value":`+s+`}`), &out), s)
+		require.Equal(t, expected, out.Value, s)
+	}
+
+	invalid := map[string]error{
+		`-0.00000001`:       amount.ErrOutOfRange,
+		`21000000.00000001`: amount.ErrOutOfRange,
+		`1e100`:             amount.ErrOutOfRange,
+		`0.000000001`:       amount.ErrTooPrecise,
+		`1e-9`:              amount.ErrTooPrecise,
+		`"1 BTC"`:           amount.ErrInvalidAmount,
+		`"0x10"`:            amount.ErrInvalidAmount,
+		`"1e"`:              amount.ErrInvalidAmount,
+		`true`:              amount.ErrInvalidAmount,
+	}
+
+	for s, expected := range invalid {
+		var out output
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"value":`+s+`}`), &out), expected, s)
+	}
+
+	out := output{Value: 7}
+	require.NoError(t, json.Unmarshal([]byte(`{"value":null}`), &out))
+	require.Equal(t, amount.Amount(7), out.Value)
+}
+
+func FuzzAmountRoundTrip(f *testing.F) {
+	f.Add(int64(0), 8)
+	f.Add(int64(-1), 5)
+	f.Add(int64(amount.MaxMoney), 0)
+
+	f.Fuzz(func(t *testing.T, v int64, unit int) {
+		units := []amount.Unit{amount.UnitBTC, amount.UnitMilliBTC, amount.UnitMicroBTC, amount.UnitSatoshi}
+		u := units[uint(unit)%uint(len(units))]
+		a := amount.Amount(v) % (amount.MaxMoney + 1)
+
+		parsed, err := amount.ParseAmount(a.Format(u))
+		require.NoError(t, err)
+		require.Equal(t, a, parsed)
+
+		encoded, err := json.Marshal(a)
+		require.NoError(t, err)
+
+		var decoded amount.Amount
+		err = json.Unmarshal(encoded, &decoded)
+		if !a.InMoneyRange() {
+			require.ErrorIs(t, err, amount.ErrOutOfRange)
+			return
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, a, decoded)
+	})
+}
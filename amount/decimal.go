@@ -0,0 +1,141 @@
+package amount
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Exact decimal parsing and formatting of fixed point integers, a value
+// v with d decimals stands for v / 10^d. The grammar is the one of
+// Bitcoin Core's ParseFixedPoint
+//
+//	[-](0|[1-9][0-9]*)[.[0-9]+][(e|E)[+|-][0-9]+]
+//
+// where the exponent is only accepted in JSON numbers
+
+// maxExponentDigits bounds the work done on absurd exponents
+const maxExponentDigits = 4
+
+var ten = big.NewInt(10)
+
+func parseFixedPoint(s string, decimals int, exponent bool) (int64, error) {
+	rest := s
+	negative := strings.HasPrefix(rest, "-")
+	if negative {
+		rest = rest[1:]
+	}
+
+	intPart := leadingDigits(rest)
+	rest = rest[len(intPart):]
+	if intPart == "" || (len(intPart) > 1 && intPart[0] == '0') {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	var fracPart string
+	if strings.HasPrefix(rest, ".") {
+		fracPart = leadingDigits(rest[1:])
+		rest = rest[1+len(fracPart):]
+		if fracPart == "" {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+	}
+
+	scale := decimals - len(fracPart)
+	if exponent && (strings.HasPrefix(rest, "e") || strings.HasPrefix(rest, "E")) {
+		rest = rest[1:]
+		expNegative := strings.HasPrefix(rest, "-")
+		if expNegative || strings.HasPrefix(rest, "+") {
+			rest = rest[1:]
+		}
+
+		expPart := leadingDigits(rest)
+		rest = rest[len(expPart):]
+		if expPart == "" {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+
+		expPart = strings.TrimLeft(expPart, "0")
+		if len(expPart) > maxExponentDigits {
+			return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+		}
+
+		var exp int
+		for _, c := range expPart {
+			exp = exp*10 + int(c-'0')
+		}
+
+		if expNegative {
+			exp = -exp
+		}
+
+		scale += exp
+	}
+
+	if rest != "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	if scale < 0 {
+		// only zeros may be dropped
+		cut := max(len(digits)+scale, 0)
+		if strings.Trim(digits[cut:], "0") != "" {
+			return 0, fmt.Errorf("%w: %q has more than %d decimals", ErrTooPrecise, s, decimals)
+		}
+
+		digits, scale = digits[:cut], 0
+	}
+
+	if digits == "" {
+		return 0, nil
+	}
+
+	// an int64 has at most 19 digits
+	if len(digits)+scale > 19 {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+	}
+
+	v, _ := big.NewInt(0).SetString(digits, 10)
+	v.Mul(v, big.NewInt(0).Exp(ten, big.NewInt(int64(scale)), nil))
+	if negative {
+		v.Neg(v)
+	}
+
+	if !v.IsInt64() {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+	}
+
+	return v.Int64(), nil
+}
+
+// formatFixedPoint writes v with the given decimals, trailing zeros of
+// the fraction are dropped when trim is set
+func formatFixedPoint(v int64, decimals int, trim bool) string {
+	var sign string
+	magnitude := uint64(v)
+	if v < 0 {
+		sign, magnitude = "-", -magnitude
+	}
+
+	digits := fmt.Sprintf("%0*d", decimals+1, magnitude)
+	intPart, fracPart := digits[:len(digits)-decimals], digits[len(digits)-decimals:]
+	if trim {
+		fracPart = strings.TrimRight(fracPart, "0")
+	}
+
+	if fracPart == "" {
+		return sign + intPart
+	}
+
+	return sign + intPart + "." + fracPart
+}
+
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	return s[:i]
+}
@@ -0,0 +1,163 @@
+package amount
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// FeeRate is a fee per 1000 virtual bytes in satoshis, the unit Bitcoin
+// Core's CFeeRate stores. A rate in sat/vB is the same number with three
+// decimals and one in BTC/kvB the same number with eight
+
+type FeeRate int64
+
+// FeeUnit is a unit fee rates are written in, its value is the number
+// of decimals of a rate in that unit
+type FeeUnit int
+
+const (
+	SatPerVByte FeeUnit = 3
+	BTCPerKvB   FeeUnit = 8
+)
+
+// feeUnitSymbols maps the accepted spellings of the fee units
+var feeUnitSymbols = map[string]FeeUnit{
+	"sat/vB":  SatPerVByte,
+	"BTC/kvB": BTCPerKvB,
+}
+
+func (u FeeUnit) String() string {
+	switch u {
+	case SatPerVByte:
+		return "sat/vB"
+	case BTCPerKvB:
+		return "BTC/kvB"
+	default:
+		return fmt.Sprintf("FeeUnit(%d)", int(u))
+	}
+}
+
+func (u FeeUnit) valid() bool {
+	return u == SatPerVByte || u == BTCPerKvB
+}
+
+// NewFeeRate returns the rate of paying fee for vsize virtual bytes,
+// rounded down. An empty size has a zero rate. Fees outside the money
+// range are rejected, which also keeps fee * 1000 from overflowing
+func NewFeeRate(fee Amount, vsize int64) (FeeRate, error) {
+	if !fee.InMoneyRange() {
+		return 0, fmt.Errorf("%w: fee %d", ErrOutOfRange, fee)
+	}
+
+	if vsize <= 0 {
+		return 0, nil
+	}
+
+	return FeeRate(int64(fee) * 1000 / vsize), nil
+}
+
+// ParseFeeRate parses a decimal number followed by its unit, separated
+// by at most one space, as in "12.5 sat/vB" or "0.0001 BTC/kvB"
+func ParseFeeRate(s string) (FeeRate, error) {
+	number, symbol := splitUnit(s)
+
+	u, ok := feeUnitSymbols[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, s)
+	}
+
+	return ParseFeeRateUnit(number, u)
+}
+
+// ParseFeeRateUnit parses a non negative decimal number of unit u
+// without a suffix, rates above MaxMoney per kvB are rejected
+func ParseFeeRateUnit(s string, u FeeUnit) (FeeRate, error) {
+	if !u.valid() {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownUnit, u)
+	}
+
+	v, err := parseFixedPoint(s, int(u), false)
+	if err != nil {
+		return 0, err
+	}
+
+	if !Amount(v).InMoneyRange() {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+	}
+
+	return FeeRate(v), nil
+}
+
+// Fee returns the fee paid by vsize virtual bytes at rate r, rounded up
+// so the rate is met. As in Bitcoin Core a non zero rate never rounds
+// the fee of a non empty transaction to zero. The product is computed on
+// 128 bits, ErrOverflow is returned when the fee does not fit an Amount
+func (r FeeRate) Fee(vsize int64) (Amount, error) {
+	negative := (r < 0) != (vsize < 0)
+
+	hi, lo := bits.Mul64(abs(int64(r)), abs(vsize))
+	if hi >= 1000 {
+		return 0, fmt.Errorf("%w: %d * %d / 1000", ErrOverflow, r, vsize)
+	}
+
+	q, rem := bits.Div64(hi, lo, 1000)
+	// truncation rounds negative fees up already
+	if rem > 0 && !negative {
+		q++
+	}
+
+	if q > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %d * %d / 1000", ErrOverflow, r, vsize)
+	}
+
+	fee := int64(q)
+	if negative {
+		fee = -fee
+	}
+
+	if fee == 0 && vsize != 0 {
+		switch {
+		case r > 0:
+			fee = 1
+		case r < 0:
+			fee = -1
+		}
+	}
+
+	return Amount(fee), nil
+}
+
+// abs returns the magnitude of v, math.MinInt64 included
+func abs(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v)
+	}
+
+	return uint64(v)
+}
+
+// Format writes r in unit u with all its decimals, the way Bitcoin Core
+// prints fee rates
+func (r FeeRate) Format(u FeeUnit) string {
+	if !u.valid() {
+		panic(fmt.Sprintf("unknown fee unit %s", u))
+	}
+
+	return formatFixedPoint(int64(r), int(u), false) + " " + u.String()
+}
+
+func (r FeeRate) String() string {
+	return r.Format(BTCPerKvB)
+}
+
+// MarshalJSON encodes r as a number of BTC/kvB, the unit of Bitcoin
+// Core's RPC fee rates
+func (r FeeRate) MarshalJSON() ([]byte, error) {
+	return Amount(r).MarshalJSON()
+}
+
+// UnmarshalJSON accepts a number of BTC/kvB with the rules of amounts
+func (r *FeeRate) UnmarshalJSON(data []byte) error {
+	return (*Amount)(r).UnmarshalJSON(data)
+}
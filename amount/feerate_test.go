@@ -0,0 +1,127 @@
+package amount_test
+
+import (
+	"amount"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireFeeRate(t *testing.T, expected amount.FeeRate, fee amount.Amount, vsize int64) {
+	t.Helper()
+
+	r, err := amount.NewFeeRate(fee, vsize)
+	require.NoError(t, err)
+	require.Equal(t, expected, r)
+}
+
+func TestFeeRate(t *testing.T) {
+	r, err := amount.NewFeeRate(1000, 250)
+	require.NoError(t, err)
+	require.Equal(t, amount.FeeRate(4000), r)
+	require.Equal(t, "4.000 sat/vB", r.Format(amount.SatPerVByte))
+	require.Equal(t, "0.00004000 BTC/kvB", r.String())
+	requireFeeRate(t, 0, 1000, 0)
+
+	// rates are rounded down, fees up
+	r, err = amount.NewFeeRate(1000, 141)
+	require.NoError(t, err)
+	require.Equal(t, amount.FeeRate(7092), r)
+	requireFee(t, 1000, r, 141)
+	requireFee(t, 1, 1, 1)
+	requireFee(t, -1, -1, 1)
+	requireFee(t, 0, 1, 0)
+	requireFee(t, 1411, 10_001, 141)
+	requireFee(t, -1410, -10_001, 141)
+
+	valid := map[string]amount.FeeRate{
+		"1 sat/vB":           1000,
+		"12.5 sat/vB":        12_500,
+		"0.001sat/vB":        1,
+		"0.0001 BTC/kvB":     10_000,
+		"0.00001000 BTC/kvB": 1000,
+	}
+
+	for s, expected := range valid {
+		r, err := amount.ParseFeeRate(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, r, s)
+	}
+
+	invalid := map[string]error{
+		"1 sat/kvB":           amount.ErrUnknownUnit,
+		"1 BTC":               amount.ErrUnknownUnit,
+		"0.0001 sat/vB":       amount.ErrTooPrecise,
+		"0.000000001 BTC/kvB": amount.ErrTooPrecise,
+		"-1 sat/vB":           amount.ErrOutOfRange,
+		"1e3 sat/vB":          amount.ErrInvalidAmount,
+	}
+
+	for s, expected := range invalid {
+		_, err := amount.ParseFeeRate(s)
+		require.ErrorIs(t, err, expected, s)
+	}
+
+	require.Panics(t, func() { r.Format(amount.FeeUnit(0)) })
+}
+
+func TestFeeOverflow(t *testing.T) {
+	// the largest rate ParseFeeRateUnit accepts
+	r, err := amount.ParseFeeRateUnit("21000000", amount.BTCPerKvB)
+	require.NoError(t, err)
+	require.Equal(t, amount.FeeRate(amount.MaxMoney), r)
+
+	// 2.1e15 * 5000 does not fit 63 bits, the fee does
+	requireFee(t, 5*amount.MaxMoney, r, 5000)
+	requireFee(t, amount.MaxMoney/1000*4_000_000, r, 4_000_000)
+	requireFee(t, math.MaxInt64, 1000, math.MaxInt64)
+	requireFee(t, -math.MaxInt64, -1000, math.MaxInt64)
+
+	for _, vsize := range []int64{4_400_000_000, math.MaxInt64, math.MinInt64} {
+		_, err = r.Fee(vsize)
+		require.ErrorIs(t, err, amount.ErrOverflow, vsize)
+	}
+
+	_, err = amount.FeeRate(1001).Fee(math.MaxInt64)
+	require.ErrorIs(t, err, amount.ErrOverflow)
+}
+
+func requireFee(t *testing.T, expected amount.Amount, r amount.FeeRate, vsize int64) {
+	t.Helper()
+
+	fee, err := r.Fee(vsize)
+	require.NoError(t, err)
+	require.Equal(t, expected, fee)
+}
+
+func TestFeeRateJSON(t *testing.T) {
+	// the shape of estimatesmartfee results
+	var estimate struct {
+		FeeRate amount.FeeRate `json:"feerate"`
+		Blocks  int            `json:"blocks"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"feerate":0.00012345,"blocks":2}`), &estimate))
+	require.Equal(t, amount.FeeRate(12_345), estimate.FeeRate)
+	require.Equal(t, "12.345 sat/vB", estimate.FeeRate.Format(amount.SatPerVByte))
+
+	encoded, err := json.Marshal(estimate)
+	require.NoError(t, err)
+	require.Equal(t, `{"feerate":0.00012345,"blocks":2}`, string(encoded))
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"feerate":-1}`), &estimate), amount.ErrOutOfRange)
+}
+
+func TestNewFeeRateRange(t *testing.T) {
+	requireFeeRate(t, 0, 0, 1)
+	requireFeeRate(t, amount.FeeRate(amount.MaxMoney)*1000, amount.MaxMoney, 1)
+	requireFeeRate(t, amount.FeeRate(amount.MaxMoney), amount.MaxMoney, 1000)
+
+	// fee * 1000 would wrap around above 9.2e15 sat
+	for _, fee := range []amount.Amount{-1, amount.MaxMoney + 1, math.MaxInt64/1000 + 1, math.MinInt64} {
+		_, err := amount.NewFeeRate(fee, 1)
+		require.ErrorIs(t, err, amount.ErrOutOfRange, fee)
+	}
+}
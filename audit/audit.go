@@ -0,0 +1,488 @@
+// Package audit looks for leaked or weak ECDSA keys among historical
+// signatures: nonces reused by a key or shared across keys, nonces small
+// enough to brute force, high-S values, unusually small r values and keys
+// whose r or s values are not uniformly distributed
+package audit
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"ecc"
+)
+
+type FindingType string
+
+const (
+	InvalidRecord    FindingType = "invalid_record"
+	InvalidSignature FindingType = "invalid_signature"
+	HighS            FindingType = "high_s"
+	SmallR           FindingType = "small_r"
+	NonceReuse       FindingType = "nonce_reuse"
+	SharedNonce      FindingType = "shared_nonce"
+	WeakNonce        FindingType = "weak_nonce"
+	BiasedNonce      FindingType = "biased_nonce"
+
+	// DefaultSmallNonceBound is the number of nonces, starting from 1,
+	// tested against every r value
+	DefaultSmallNonceBound = 1 << 12
+
+	// DefaultBiasMinSignatures is the number of valid signatures a key
+	// needs before its r and s values are tested for bias, five per
+	// bucket of the chi-square test
+	DefaultBiasMinSignatures = 5 << biasBits
+
+	// r values with fewer bits happen with probability 2 ^ -32
+	smallRBits = 224
+
+	// the bias test buckets r and s by their 4 bits below the top one.
+	// The top bit is skipped since low-R grinding clears it in r and
+	// low-S normalisation in s
+	biasBits  = 4
+	biasShift = 255 - biasBits
+
+	// chi-square critical value for 15 degrees of freedom at p = 0.001
+	biasCritical = 37.697
+)
+
+// Finding is a weakness found in one or more records, identified by
+// their position in the ingestion order
+type Finding struct {
+	Type    FindingType `json:"type"`
+	PubKey  string      `json:"pubkey,omitempty"`
+	Records []int       `json:"records"`
+	Detail  string      `json:"detail,omitempty"`
+
+	// PrivateKey is the hex encoded secret when the finding leaks it
+	PrivateKey string `json:"private_key,omitempty"`
+}
+
+// KeyStats summarises the signatures of a single public key
+type KeyStats struct {
+	PubKey     string `json:"pubkey"`
+	Signatures int    `json:"signatures"`
+	DistinctR  int    `json:"distinct_r"`
+	HighS      int    `json:"high_s"`
+	Invalid    int    `json:"invalid"`
+	Recovered  bool   `json:"recovered"`
+}
+
+type Report struct {
+	Records       int         `json:"records"`
+	RecoveredKeys int         `json:"recovered_keys"`
+	Keys          []*KeyStats `json:"keys"`
+	Findings      []*Finding  `json:"findings"`
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+type Auditor struct {
+	// SmallNonceBound overrides DefaultSmallNonceBound when positive
+	SmallNonceBound int
+	// BiasMinSignatures overrides DefaultBiasMinSignatures when positive
+	BiasMinSignatures int
+
+	count    int
+	records  []*record
+	findings []*Finding
+}
+
+func New() *Auditor {
+	return &Auditor{}
+}
+
+// Add ingests a record, malformed records are reported
+// as findings instead of aborting the audit
+func (a *Auditor) Add(rec Record) {
+	index := a.count
+	a.count++
+
+	parsed, err := parseRecord(index, rec)
+	if err != nil {
+		a.findings = append(a.findings, &Finding{
+			Type:    InvalidRecord,
+			PubKey:  rec.PubKey,
+			Records: []int{index},
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	a.records = append(a.records, parsed)
+}
+
+// ReadJSONLines ingests one JSON encoded Record per line
+func (a *Auditor) ReadJSONLines(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		a.Add(rec)
+	}
+
+	return scanner.Err()
+}
+
+// Report runs every check over the ingested records
+func (a *Auditor) Report() *Report {
+	var (
+		findings  = append([]*Finding{}, a.findings...)
+		stats     = make(map[string]*KeyStats)
+		recovered = make(map[string]*big.Int)
+		byR       = make(map[string][]*record)
+		byKey     = make(map[string][]*record)
+		halfN     = big.NewInt(0).Rsh(ecc.BitcoinN, 1)
+	)
+
+	for _, rec := range a.records {
+		st, ok := stats[rec.key]
+		if !ok {
+			st = &KeyStats{PubKey: rec.key}
+			stats[rec.key] = st
+		}
+
+		st.Signatures++
+		r := rec.sig.R()
+		rKey := r.Text(16)
+		byR[rKey] = append(byR[rKey], rec)
+
+		if !rec.verify() {
+			st.Invalid++
+			findings = append(findings, &Finding{
+				Type:    InvalidSignature,
+				PubKey:  rec.key,
+				Records: []int{rec.index},
+			})
+			continue
+		}
+
+		byKey[rec.key] = append(byKey[rec.key], rec)
+
+		if rec.sig.S().Cmp(halfN) > 0 {
+			st.HighS++
+			findings = append(findings, &Finding{
+				Type:    HighS,
+				PubKey:  rec.key,
+				Records: []int{rec.index},
+			})
+		}
+
+		if r.BitLen() <= smallRBits {
+			findings = append(findings, &Finding{
+				Type:    SmallR,
+				PubKey:  rec.key,
+				Records: []int{rec.index},
+				Detail:  fmt.Sprintf("r has only %d bits", r.BitLen()),
+			})
+		}
+	}
+
+	findings = append(findings, a.checkWeakNonces(recovered)...)
+	findings = append(findings, a.checkReusedNonces(byR, recovered)...)
+	findings = append(findings, a.checkBiasedNonces(byKey)...)
+
+	report := &Report{Records: a.count, Findings: findings}
+	for key, st := range stats {
+		st.Recovered = recovered[key] != nil
+		report.Keys = append(report.Keys, st)
+		if st.Recovered {
+			report.RecoveredKeys++
+		}
+	}
+
+	for _, recs := range byR {
+		seen := make(map[string]bool)
+		for _, rec := range recs {
+			if !seen[rec.key] {
+				seen[rec.key] = true
+				stats[rec.key].DistinctR++
+			}
+		}
+	}
+
+	sort.Slice(report.Keys, func(i, j int) bool {
+		return report.Keys[i].PubKey < report.Keys[j].PubKey
+	})
+
+	return report
+}
+
+// checkWeakNonces looks for nonces that are small, equal to (n + 1) / 2,
+// whose r has a well known small x coordinate, or equal to the message hash
+func (a *Auditor) checkWeakNonces(recovered map[string]*big.Int) []*Finding {
+	var findings []*Finding
+
+	table := a.weakNonceTable()
+	for _, rec := range a.records {
+		k, ok := table[rec.sig.R().Text(16)]
+		detail := fmt.Sprintf("nonce %s", k)
+		if !ok {
+			// nonce equal to the signed message
+			if rec.z.Sign() == 0 || ecc.BitcoingGenPoint.ScalarMul(rec.z).Sec(true)[2:] != fmt.Sprintf("%064x", rec.sig.R()) {
+				continue
+			}
+
+			k, detail = rec.z, "nonce equals the message hash"
+		}
+
+		d := rec.secretFromNonce(k)
+		if d == nil {
+			continue
+		}
+
+		recovered[rec.key] = d
+		findings = append(findings, &Finding{
+			Type:       WeakNonce,
+			PubKey:     rec.key,
+			Records:    []int{rec.index},
+			Detail:     detail,
+			PrivateKey: secretHex(d),
+		})
+	}
+
+	return findings
+}
+
+// checkReusedNonces groups signatures by r. Two signatures of the same key
+// leak its secret, a nonce shared across keys leaks every key once one of
+// them is known
+func (a *Auditor) checkReusedNonces(byR map[string][]*record, recovered map[string]*big.Int) []*Finding {
+	var (
+		findings []*Finding
+		shared   [][]*record
+	)
+
+	rs := make([]string, 0, len(byR))
+	for r := range byR {
+		rs = append(rs, r)
+	}
+	sort.Strings(rs)
+
+	for _, r := range rs {
+		recs := byR[r]
+		if len(recs) < 2 {
+			continue
+		}
+
+		byKey := make(map[string][]*record)
+		for _, rec := range recs {
+			byKey[rec.key] = append(byKey[rec.key], rec)
+		}
+
+		if len(byKey) > 1 {
+			shared = append(shared, recs)
+		}
+
+		for key, keyRecs := range byKey {
+			if len(keyRecs) < 2 {
+				continue
+			}
+
+			finding := &Finding{
+				Type:    NonceReuse,
+				PubKey:  key,
+				Records: indexes(keyRecs),
+				Detail:  fmt.Sprintf("r %s", r),
+			}
+
+			for i := 1; i < len(keyRecs) && finding.PrivateKey == ""; i++ {
+				if d := recoverFromReuse(keyRecs[0], keyRecs[i]); d != nil {
+					recovered[key] = d
+					finding.PrivateKey = secretHex(d)
+				}
+			}
+
+			findings = append(findings, finding)
+		}
+	}
+
+	// propagate recovered keys through shared nonces until nothing changes
+	sharedFindings := make([]*Finding, len(shared))
+	for progress := true; progress; {
+		progress = false
+
+		for i, recs := range shared {
+			if sharedFindings[i] == nil {
+				sharedFindings[i] = &Finding{
+					Type:    SharedNonce,
+					Records: indexes(recs),
+					Detail:  fmt.Sprintf("r %s", recs[0].sig.R().Text(16)),
+				}
+			}
+
+			var k *big.Int
+			for _, rec := range recs {
+				if d := recovered[rec.key]; d != nil {
+					k = rec.nonceFromSecret(d)
+					break
+				}
+			}
+
+			if k == nil {
+				continue
+			}
+
+			for _, rec := range recs {
+				if recovered[rec.key] != nil {
+					continue
+				}
+
+				if d := rec.secretFromNonce(k); d != nil {
+					recovered[rec.key] = d
+					progress = true
+					findings = append(findings, &Finding{
+						Type:       SharedNonce,
+						PubKey:     rec.key,
+						Records:    []int{rec.index},
+						Detail:     "secret recovered from a nonce shared with a leaked key",
+						PrivateKey: secretHex(d),
+					})
+				}
+			}
+		}
+	}
+
+	return append(findings, sharedFindings...)
+}
+
+// checkBiasedNonces runs a chi-square test on the distribution of the r
+// and s values of every key with enough valid signatures. Both are
+// uniform for a sound nonce generator, a skew points at a generator
+// whose output is partly predictable even when no nonce repeats
+func (a *Auditor) checkBiasedNonces(byKey map[string][]*record) []*Finding {
+	minSignatures := a.BiasMinSignatures
+	if minSignatures <= 0 {
+		minSignatures = DefaultBiasMinSignatures
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for key, recs := range byKey {
+		if len(recs) >= minSignatures {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var (
+		findings []*Finding
+		halfN    = big.NewInt(0).Rsh(ecc.BitcoinN, 1)
+	)
+
+	for _, key := range keys {
+		var rCounts, sCounts [1 << biasBits]int
+
+		recs := byKey[key]
+		for _, rec := range recs {
+			s := rec.sig.S()
+			if s.Cmp(halfN) > 0 {
+				s.Sub(ecc.BitcoinN, s)
+			}
+
+			rCounts[biasBucket(rec.sig.R())]++
+			sCounts[biasBucket(s)]++
+		}
+
+		for _, c := range []struct {
+			name   string
+			counts []int
+		}{{"r", rCounts[:]}, {"s", sCounts[:]}} {
+			chi := chiSquare(c.counts, len(recs))
+			if chi <= biasCritical {
+				continue
+			}
+
+			findings = append(findings, &Finding{
+				Type:    BiasedNonce,
+				PubKey:  key,
+				Records: indexes(recs),
+				Detail: fmt.Sprintf("chi-square %.1f over bits %d to 254 of %s in %d signatures, above %.3f",
+					chi, biasShift, c.name, len(recs), biasCritical),
+			})
+		}
+	}
+
+	return findings
+}
+
+// biasBucket returns the biasBits bits of v below bit 255
+func biasBucket(v *big.Int) int {
+	return int(big.NewInt(0).Rsh(v, biasShift).Int64() & (1<<biasBits - 1))
+}
+
+// chiSquare measures how far counts are from total spread evenly
+func chiSquare(counts []int, total int) float64 {
+	expected := float64(total) / float64(len(counts))
+
+	var chi float64
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi += d * d / expected
+	}
+
+	return chi
+}
+
+// weakNonceTable maps the r produced by every weak nonce to the nonce
+func (a *Auditor) weakNonceTable() map[string]*big.Int {
+	bound := a.SmallNonceBound
+	if bound <= 0 {
+		bound = DefaultSmallNonceBound
+	}
+
+	var (
+		g      = ecc.NewJacobianPoint(ecc.BitcoingGenPoint)
+		curr   = g
+		points = make([]*ecc.JacobianPoint, 0, bound+1)
+		nonces = make([]*big.Int, 0, bound+1)
+	)
+
+	for k := 1; k <= bound; k++ {
+		points = append(points, curr)
+		nonces = append(nonces, big.NewInt(int64(k)))
+		curr = curr.Add(g)
+	}
+
+	// 1 / 2 gives the smallest known x coordinate on the curve
+	half := big.NewInt(0).ModInverse(big.NewInt(2), ecc.BitcoinN)
+	points = append(points, g.ScalarMul(half))
+	nonces = append(nonces, half)
+
+	table := make(map[string]*big.Int, len(points))
+	for i, p := range ecc.NormalizePoints(points) {
+		x, _ := big.NewInt(0).SetString(p.Sec(true)[2:], 16)
+		table[x.Mod(x, ecc.BitcoinN).Text(16)] = nonces[i]
+	}
+
+	return table
+}
+
+func indexes(recs []*record) []int {
+	idx := make([]int, len(recs))
+	for i, rec := range recs {
+		idx[i] = rec.index
+	}
+
+	return idx
+}
+
+func secretHex(d *big.Int) string {
+	buf := make([]byte, 32)
+	d.FillBytes(buf)
+	return hex.EncodeToString(buf)
+}
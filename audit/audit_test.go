@@ -0,0 +1,279 @@
+package audit_test
+
+import (
+	"audit"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"ecc"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sign produces a record signed with a chosen nonce, s is not normalised
+func sign(secret, z int64, k *big.Int) audit.Record {
+	key := ecc.NewPrivateKey(big.NewInt(secret))
+	r, _ := big.NewInt(0).SetString(ecc.BitcoingGenPoint.ScalarMul(k).Sec(true)[2:], 16)
+	r.Mod(r, ecc.BitcoinN)
+
+	s := big.NewInt(0).Mul(r, big.NewInt(secret))
+	s.Add(s, big.NewInt(z))
+	s.Mul(s, big.NewInt(0).ModInverse(k, ecc.BitcoinN)).Mod(s, ecc.BitcoinN)
+
+	sig := ecc.NewSignature(ecc.NewFieldElement(ecc.BitcoinN, r), ecc.NewFieldElement(ecc.BitcoinN, s))
+	return audit.Record{
+		PubKey:    key.PublicKey().Sec(true),
+		Z:         fmt.Sprintf("%064x", z),
+		Signature: hex.EncodeToString(sig.Der()),
+	}
+}
+
+// signWithR signs like sign when r, the x coordinate of k * G, is
+// already known, s is normalised to low-S when lowS is set
+func signWithR(secret, z int64, k, r *big.Int, lowS bool) audit.Record {
+	s := big.NewInt(0).Mul(r, big.NewInt(secret))
+	s.Add(s, big.NewInt(z))
+	s.Mul(s, big.NewInt(0).ModInverse(k, ecc.BitcoinN)).Mod(s, ecc.BitcoinN)
+	if lowS && s.Cmp(big.NewInt(0).Rsh(ecc.BitcoinN, 1)) > 0 {
+		s.Sub(ecc.BitcoinN, s)
+	}
+
+	sig := ecc.NewSignature(ecc.NewFieldElement(ecc.BitcoinN, r), ecc.NewFieldElement(ecc.BitcoinN, s))
+	return audit.Record{
+		PubKey:    ecc.NewPrivateKey(big.NewInt(secret)).PublicKey().Sec(true),
+		Z:         fmt.Sprintf("%064x", z),
+		Signature: hex.EncodeToString(sig.Der()),
+	}
+}
+
+func secretHex(secret int64) string {
+	return fmt.Sprintf("%064x", secret)
+}
+
+func findingsOf(report *audit.Report, typ audit.FindingType) []*audit.Finding {
+	var found []*audit.Finding
+	for _, f := range report.Findings {
+		if f.Type == typ {
+			found = append(found, f)
+		}
+	}
+
+	return found
+}
+
+func TestNonceReuse(t *testing.T) {
+	a := audit.New()
+	a.SmallNonceBound = 1
+
+	k := big.NewInt(0xfeedface1234)
+	a.Add(sign(0xc0ffee, 1000, k))
+	a.Add(sign(0xc0ffee, 2000, big.NewInt(0xfeedface1235)))
+	a.Add(sign(0xc0ffee, 3000, k))
+
+	report := a.Report()
+	require.Equal(t, 3, report.Records)
+	require.Equal(t, 1, report.RecoveredKeys)
+
+	reuse := findingsOf(report, audit.NonceReuse)
+	require.Len(t, reuse, 1)
+	require.Equal(t, []int{0, 2}, reuse[0].Records)
+	require.Equal(t, secretHex(0xc0ffee), reuse[0].PrivateKey)
+
+	require.Len(t, report.Keys, 1)
+	require.Equal(t, 3, report.Keys[0].Signatures)
+	require.Equal(t, 2, report.Keys[0].DistinctR)
+	require.True(t, report.Keys[0].Recovered)
+}
+
+func TestSharedNonceAcrossKeys(t *testing.T) {
+	a := audit.New()
+	a.SmallNonceBound = 1
+
+	k := big.NewInt(0xabcdef987)
+	// the first key leaks by reusing k, the second only shares it
+	a.Add(sign(111111, 1, k))
+	a.Add(sign(111111, 2, k))
+	a.Add(sign(222222, 3, k))
+	a.Add(sign(333333, 4, big.NewInt(0xabcdef988)))
+
+	report := a.Report()
+	require.Equal(t, 2, report.RecoveredKeys)
+
+	var recovered []*audit.Finding
+	for _, f := range findingsOf(report, audit.SharedNonce) {
+		if f.PrivateKey != "" {
+			recovered = append(recovered, f)
+		}
+	}
+
+	require.Len(t, recovered, 1)
+	require.Equal(t, []int{2}, recovered[0].Records)
+	require.Equal(t, secretHex(222222), recovered[0].PrivateKey)
+}
+
+func TestWeakNonces(t *testing.T) {
+	a := audit.New()
+	a.SmallNonceBound = 64
+
+	half := big.NewInt(0).ModInverse(big.NewInt(2), ecc.BitcoinN)
+	a.Add(sign(0x1234, 777, big.NewInt(42)))
+	a.Add(sign(0x5678, 0xbeef, big.NewInt(0xbeef)))
+	a.Add(sign(0x9abc, 5, big.NewInt(65)))
+	a.Add(sign(0xdef0, 6, half))
+
+	report := a.Report()
+	require.Equal(t, 3, report.RecoveredKeys)
+
+	weak := findingsOf(report, audit.WeakNonce)
+	require.Len(t, weak, 3)
+	require.Equal(t, secretHex(0x1234), weak[0].PrivateKey)
+	require.Equal(t, "nonce 42", weak[0].Detail)
+	require.Equal(t, secretHex(0x5678), weak[1].PrivateKey)
+	require.Equal(t, "nonce equals the message hash", weak[1].Detail)
+	require.Equal(t, []int{3}, weak[2].Records)
+	require.Equal(t, secretHex(0xdef0), weak[2].PrivateKey)
+
+	// 1 / 2 * G has an x coordinate of only 166 bits
+	small := findingsOf(report, audit.SmallR)
+	require.Len(t, small, 1)
+	require.Equal(t, []int{3}, small[0].Records)
+}
+
+// flipS returns the record with s replaced by n - s, which also verifies
+func flipS(t *testing.T, rec audit.Record) audit.Record {
+	der, err := hex.DecodeString(rec.Signature)
+	require.NoError(t, err)
+
+	sig, err := ecc.ParseDER(der)
+	require.NoError(t, err)
+
+	s := big.NewInt(0).Sub(ecc.BitcoinN, sig.S())
+	flipped := ecc.NewSignature(ecc.NewFieldElement(ecc.BitcoinN, sig.R()), ecc.NewFieldElement(ecc.BitcoinN, s))
+	rec.Signature = hex.EncodeToString(flipped.Der())
+	return rec
+}
+
+func TestBiasedNonces(t *testing.T) {
+	const signatures = 32
+
+	// r of the consecutive nonces k0, k0 + 1, ...
+	k0, _ := big.NewInt(0).SetString("5f1e7c9a3b2d4e6f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7", 16)
+	g := ecc.NewJacobianPoint(ecc.BitcoingGenPoint)
+	curr := ecc.NewJacobianPoint(ecc.BitcoingGenPoint.ScalarMul(k0))
+
+	points := make([]*ecc.JacobianPoint, 0, 1024)
+	for i := 0; i < cap(points); i++ {
+		points = append(points, curr)
+		curr = curr.Add(g)
+	}
+
+	a := audit.New()
+	a.SmallNonceBound = 1
+	a.BiasMinSignatures = signatures
+
+	var biased, sound int
+	for i, p := range ecc.NormalizePoints(points) {
+		k := big.NewInt(0).Add(k0, big.NewInt(int64(i)))
+		r := p.X()
+		top := big.NewInt(0).Rsh(r, 251).Int64()
+
+		// a generator only producing r with bits 251 to 255 cleared
+		if top == 0 && biased < signatures {
+			a.Add(signWithR(0xb1a5, int64(i+1), k, r, false))
+			biased++
+			continue
+		}
+
+		// low-R grinding and low-S normalisation, as Bitcoin Core does,
+		// are not a bias
+		if top < 0x10 && sound < signatures {
+			a.Add(signWithR(0x50d, int64(i+1), k, r, true))
+			sound++
+		}
+	}
+
+	require.Equal(t, signatures, biased)
+	require.Equal(t, signatures, sound)
+
+	report := a.Report()
+	require.Empty(t, findingsOf(report, audit.SharedNonce))
+
+	found := findingsOf(report, audit.BiasedNonce)
+	require.Len(t, found, 1)
+	require.Equal(t, ecc.NewPrivateKey(big.NewInt(0xb1a5)).PublicKey().Sec(true), found[0].PubKey)
+	require.Len(t, found[0].Records, signatures)
+	require.Regexp(t, "^chi-square 480.0 over bits 251 to 254 of r in 32 signatures", found[0].Detail)
+
+	// too few signatures to test
+	a.BiasMinSignatures = signatures + 1
+	require.Empty(t, findingsOf(a.Report(), audit.BiasedNonce))
+}
+
+func TestHighSAndInvalidRecords(t *testing.T) {
+	rec := sign(1, 1, big.NewInt(0xfffff1))
+	halfN := big.NewInt(0).Rsh(ecc.BitcoinN, 1)
+
+	for _, candidate := range []audit.Record{rec, flipS(t, rec)} {
+		der, _ := hex.DecodeString(candidate.Signature)
+		sig, err := ecc.ParseDER(der)
+		require.NoError(t, err)
+
+		a := audit.New()
+		a.SmallNonceBound = 1
+		a.Add(candidate)
+		report := a.Report()
+
+		high := sig.S().Cmp(halfN) > 0
+		require.Len(t, findingsOf(report, audit.InvalidSignature), 0)
+		require.Equal(t, high, len(findingsOf(report, audit.HighS)) == 1)
+		require.Equal(t, high, report.Keys[0].HighS == 1)
+	}
+
+	a := audit.New()
+	a.SmallNonceBound = 1
+
+	invalid := rec
+	invalid.Z = fmt.Sprintf("%064x", 4)
+	a.Add(invalid)
+	a.Add(audit.Record{PubKey: "02", Z: "00", Signature: rec.Signature})
+	a.Add(audit.Record{PubKey: rec.PubKey, Z: "zz", Signature: rec.Signature})
+	a.Add(audit.Record{PubKey: rec.PubKey, Z: "00", Signature: "3000"})
+
+	report := a.Report()
+	require.Equal(t, 4, report.Records)
+	require.Len(t, findingsOf(report, audit.InvalidRecord), 3)
+
+	bad := findingsOf(report, audit.InvalidSignature)
+	require.Len(t, bad, 1)
+	require.Equal(t, []int{0}, bad[0].Records)
+	require.Equal(t, 1, report.Keys[0].Invalid)
+}
+
+func TestReadJSONLinesAndReport(t *testing.T) {
+	var input bytes.Buffer
+	enc := json.NewEncoder(&input)
+	require.NoError(t, enc.Encode(sign(77, 1, big.NewInt(3))))
+	input.WriteString("\n")
+	require.NoError(t, enc.Encode(sign(78, 2, big.NewInt(0xffffffffff))))
+
+	a := audit.New()
+	require.NoError(t, a.ReadJSONLines(&input))
+	require.Error(t, a.ReadJSONLines(strings.NewReader("{not json}\n")))
+
+	report := a.Report()
+	require.Equal(t, 2, report.Records)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteJSON(&out))
+
+	var decoded audit.Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, *report, decoded)
+	require.Equal(t, 1, decoded.RecoveredKeys)
+	require.Equal(t, secretHex(77), findingsOf(&decoded, audit.WeakNonce)[0].PrivateKey)
+}
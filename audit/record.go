@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"ecc"
+)
+
+// Record is a signature to audit, every field is hex encoded. PubKey is
+// in SEC format, Z is the signed message hash and Signature is DER encoded
+type Record struct {
+	PubKey    string `json:"pubkey"`
+	Z         string `json:"z"`
+	Signature string `json:"signature"`
+}
+
+type record struct {
+	index  int
+	key    string
+	pubKey *ecc.Point
+	z      *big.Int
+	sig    *ecc.Signature
+}
+
+func parseRecord(index int, rec Record) (*record, error) {
+	sec, err := hex.DecodeString(rec.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("pubkey: %w", err)
+	}
+
+	pubKey, err := ecc.FromSec(bytes.NewReader(sec))
+	if err != nil {
+		return nil, fmt.Errorf("pubkey: %w", err)
+	}
+
+	z, ok := big.NewInt(0).SetString(rec.Z, 16)
+	if !ok || z.Sign() < 0 || z.BitLen() > 256 {
+		return nil, fmt.Errorf("z: invalid hex %q", rec.Z)
+	}
+
+	der, err := hex.DecodeString(rec.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+
+	sig, err := ecc.ParseDER(der)
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+
+	return &record{
+		index:  index,
+		key:    pubKey.Sec(true),
+		pubKey: pubKey,
+		z:      z.Mod(z, ecc.BitcoinN),
+		sig:    sig,
+	}, nil
+}
+
+func (r *record) verify() bool {
+	return r.pubKey.Verify(ecc.NewFieldElement(ecc.BitcoinN, r.z), r.sig)
+}
+
+// secretFromNonce solves s = (z + r * d) / k for d, the signature may
+// have been normalised to low-S so both k and -k are tried
+func (r *record) secretFromNonce(k *big.Int) *big.Int {
+	rInv := big.NewInt(0).ModInverse(r.sig.R(), ecc.BitcoinN)
+
+	for _, s := range []*big.Int{r.sig.S(), big.NewInt(0).Sub(ecc.BitcoinN, r.sig.S())} {
+		d := big.NewInt(0).Mul(s, k)
+		d.Sub(d, r.z).Mul(d, rInv).Mod(d, ecc.BitcoinN)
+		if d.Sign() != 0 && ecc.NewPrivateKey(d).PublicKey().Sec(true) == r.key {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// nonceFromSecret solves s = (z + r * d) / k for k
+func (r *record) nonceFromSecret(d *big.Int) *big.Int {
+	k := big.NewInt(0).Mul(r.sig.R(), d)
+	k.Add(k, r.z).Mul(k, big.NewInt(0).ModInverse(r.sig.S(), ecc.BitcoinN))
+	return k.Mod(k, ecc.BitcoinN)
+}
+
+// recoverFromReuse recovers the secret shared by two signatures of the
+// same key using the same nonce: k = (z1 - z2) / (s1 -+ s2)
+func recoverFromReuse(a, b *record) *big.Int {
+	dz := big.NewInt(0).Sub(a.z, b.z)
+	dz.Mod(dz, ecc.BitcoinN)
+	if dz.Sign() == 0 {
+		return nil
+	}
+
+	for _, ds := range []*big.Int{
+		big.NewInt(0).Sub(a.sig.S(), b.sig.S()),
+		big.NewInt(0).Add(a.sig.S(), b.sig.S()),
+	} {
+		ds.Mod(ds, ecc.BitcoinN)
+		if ds.Sign() == 0 {
+			continue
+		}
+
+		k := big.NewInt(0).ModInverse(ds, ecc.BitcoinN)
+		k.Mul(k, dz).Mod(k, ecc.BitcoinN)
+		if d := a.secretFromNonce(k); d != nil {
+			return d
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package base58
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"hashes"
+)
+
+// Base58 as used by Bitcoin, every leading zero byte is encoded as a
+// leading '1' and the remaining bytes as a big endian base 58 number.
+// Base58Check appends the first 4 bytes of the double SHA256 of the
+// data before encoding it
+
+const (
+	alphabet     = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	ChecksumSize = 4
+)
+
+var (
+	ErrInvalidCharacter = errors.New("invalid base58 character")
+	ErrInvalidChecksum  = errors.New("invalid base58 checksum")
+	ErrTooShort         = errors.New("base58check string is too short")
+
+	decodeMap = func() [256]int16 {
+		var m [256]int16
+		for i := range m {
+			m[i] = -1
+		}
+
+		for i := 0; i < len(alphabet); i++ {
+			m[alphabet[i]] = int16(i)
+		}
+
+		return m
+	}()
+)
+
+// CharacterError reports a character outside of the base58 alphabet,
+// it matches ErrInvalidCharacter with errors.Is
+type CharacterError struct {
+	Char     byte
+	Position int
+}
+
+func (e *CharacterError) Error() string {
+	return fmt.Sprintf("%s %q at position %d", ErrInvalidCharacter, e.Char, e.Position)
+}
+
+func (e *CharacterError) Unwrap() error {
+	return ErrInvalidCharacter
+}
+
+// ChecksumError reports a Base58Check checksum mismatch, it matches
+// ErrInvalidChecksum with errors.Is
+type ChecksumError struct {
+	Expected [ChecksumSize]byte
+	Actual   [ChecksumSize]byte
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: expected %x, got %x", ErrInvalidChecksum, e.Expected, e.Actual)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrInvalidChecksum
+}
+
+func Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) ~ 1.37 digits for each byte
+	digits := make([]byte, 0, (len(data)-zeros)*138/100+1)
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	encoded := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		encoded[i] = alphabet[0]
+	}
+
+	for i, d := range digits {
+		encoded[len(encoded)-1-i] = alphabet[d]
+	}
+
+	return string(encoded)
+}
+
+func Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	// log(58) / log(256) ~ 0.733 bytes for each digit
+	decoded := make([]byte, 0, (len(s)-zeros)*733/1000+1)
+	for i := zeros; i < len(s); i++ {
+		carry := int(decodeMap[s[i]])
+		if carry < 0 {
+			return nil, &CharacterError{Char: s[i], Position: i}
+		}
+
+		for j := range decoded {
+			carry += int(decoded[j]) * 58
+			decoded[j] = byte(carry)
+			carry >>= 8
+		}
+
+		for carry > 0 {
+			decoded = append(decoded, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	result := make([]byte, zeros+len(decoded))
+	for i, b := range decoded {
+		result[len(result)-1-i] = b
+	}
+
+	return result, nil
+}
+
+// CheckEncode encodes data followed by its checksum, versions and
+// other prefixes are expected to be part of data
+func CheckEncode(data []byte) string {
+	buf := make([]byte, 0, len(data)+ChecksumSize)
+	buf = append(buf, data...)
+	buf = append(buf, Checksum(data)...)
+	return Encode(buf)
+}
+
+// CheckDecode decodes s and verifies its checksum, returning the
+// data without it
+func CheckDecode(s string) ([]byte, error) {
+	decoded, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) < ChecksumSize {
+		return nil, ErrTooShort
+	}
+
+	data, actual := decoded[:len(decoded)-ChecksumSize], decoded[len(decoded)-ChecksumSize:]
+	expected := Checksum(data)
+	if !bytes.Equal(expected, actual) {
+		e := &ChecksumError{}
+		copy(e.Expected[:], expected)
+		copy(e.Actual[:], actual)
+		return nil, e
+	}
+
+	return data, nil
+}
+
+// Checksum returns the first 4 bytes of SHA256(SHA256(data))
+func Checksum(data []byte) []byte {
+	h := hashes.Hash256(data)
+	return h[:ChecksumSize]
+}
@@ -0,0 +1,142 @@
+package base58_test
+
+import (
+	"base58"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vectors from bitcoin core src/test/data/base58_encode_decode.json
+var vectors = []struct {
+	hex     string
+	encoded string
+}{
+	{"", ""},
+	{"61", "2g"},
+	{"626262", "a3gV"},
+	{"636363", "aPEr"},
+	{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+	{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	{"516b6fcd0f", "ABnLTmg"},
+	{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+	{"572e4794", "3EFU7m"},
+	{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+	{"10c8511e", "Rt5zm"},
+	{"00000000000000000000", "1111111111"},
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, v := range vectors {
+		data, err := hex.DecodeString(v.hex)
+		require.NoError(t, err)
+		require.Equal(t, v.encoded, base58.Encode(data))
+
+		decoded, err := base58.Decode(v.encoded)
+		require.NoError(t, err)
+		require.Equal(t, v.hex, hex.EncodeToString(decoded))
+	}
+}
+
+func TestDecodeInvalidCharacter(t *testing.T) {
+	for s, pos := range map[string]int{
+		"0":         0,
+		"1I":        1,
+		"3EFlU7":    3,
+		"abcO":      3,
+		"a b":       1,
+		"Rt5zm\x00": 5,
+	} {
+		_, err := base58.Decode(s)
+		require.ErrorIs(t, err, base58.ErrInvalidCharacter, s)
+
+		var charErr *base58.CharacterError
+		require.True(t, errors.As(err, &charErr))
+		require.Equal(t, pos, charErr.Position)
+		require.Equal(t, s[pos], charErr.Char)
+	}
+}
+
+func TestCheckEncodeDecode(t *testing.T) {
+	// the genesis block P2PKH address, version byte and key hash
+	payload, err := hex.DecodeString("0062e907b15cbf27d5425399ebf6f0fb50ebb88f18")
+	require.NoError(t, err)
+	require.Equal(t, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", base58.CheckEncode(payload))
+
+	decoded, err := base58.CheckDecode("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa")
+	require.NoError(t, err)
+	require.Equal(t, payload, decoded)
+
+	decoded, err = base58.CheckDecode(base58.CheckEncode(nil))
+	require.NoError(t, err)
+	require.Empty(t, decoded)
+
+	_, err = base58.CheckDecode("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb")
+	require.ErrorIs(t, err, base58.ErrInvalidChecksum)
+
+	var checksumErr *base58.ChecksumError
+	require.True(t, errors.As(err, &checksumErr))
+	require.NotEqual(t, checksumErr.Expected, checksumErr.Actual)
+
+	_, err = base58.CheckDecode("a3gV")
+	require.ErrorIs(t, err, base58.ErrTooShort)
+
+	_, err = base58.CheckDecode("1A1zP1eP5QGefi2DMPTfTL5SLmv7Divf0a")
+	require.ErrorIs(t, err, base58.ErrInvalidCharacter)
+}
+
+func FuzzEncodeDecode(f *testing.F) {
+	for _, v := range vectors {
+		data, _ := hex.DecodeString(v.hex)
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		decoded, err := base58.Decode(base58.Encode(data))
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(data), hex.EncodeToString(decoded))
+	})
+}
+
+func FuzzDecode(f *testing.F) {
+	for _, v := range vectors {
+		f.Add(v.encoded)
+	}
+	f.Add("0OIl")
+
+	// every valid string has a single decoding
+	f.Fuzz(func(t *testing.T, s string) {
+		decoded, err := base58.Decode(s)
+		if err != nil {
+			require.ErrorIs(t, err, base58.ErrInvalidCharacter)
+			return
+		}
+
+		require.Equal(t, s, base58.Encode(decoded))
+	})
+}
+
+func FuzzCheckEncodeDecode(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x00, 0x01})
+	f.Add([]byte{0x80, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		encoded := base58.CheckEncode(data)
+		decoded, err := base58.CheckDecode(encoded)
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(data), hex.EncodeToString(decoded))
+
+		// flipping the last character breaks the checksum
+		last := encoded[len(encoded)-1]
+		flipped := byte('2')
+		if last == flipped {
+			flipped = '3'
+		}
+
+		_, err = base58.CheckDecode(encoded[:len(encoded)-1] + string(flipped))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,308 @@
+package bech32
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bech32 (BIP173) and Bech32m (BIP350) strings are made of a human
+// readable part, the separator '1' and 5 bits values encoded with a 32
+// characters alphabet, the last 6 values being a BCH checksum. Both
+// variants only differ on the constant the checksum is xored with
+
+type Encoding int
+
+const (
+	Bech32 Encoding = iota + 1
+	Bech32m
+)
+
+const (
+	// MaxLength is the limit of BIP173, use DecodeWithLimit for
+	// formats allowing longer strings
+	MaxLength = 90
+
+	ChecksumLength = 6
+	MaxHRPLength   = 83
+
+	charset      = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+var (
+	ErrInvalidLength    = errors.New("invalid bech32 string length")
+	ErrMixedCase        = errors.New("bech32 string mixes upper and lower case")
+	ErrInvalidSeparator = errors.New("invalid bech32 separator position")
+	ErrInvalidHRP       = errors.New("invalid bech32 human readable part")
+	ErrInvalidCharacter = errors.New("invalid bech32 character")
+	ErrInvalidChecksum  = errors.New("invalid bech32 checksum")
+	ErrInvalidData      = errors.New("invalid bech32 data")
+	ErrInvalidPadding   = errors.New("invalid bech32 padding")
+
+	generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	charsetRev = func() [128]int8 {
+		var rev [128]int8
+		for i := range rev {
+			rev[i] = -1
+		}
+
+		for i := 0; i < len(charset); i++ {
+			rev[charset[i]] = int8(i)
+		}
+
+		return rev
+	}()
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case Bech32:
+		return "bech32"
+	case Bech32m:
+		return "bech32m"
+	default:
+		return fmt.Sprintf("Encoding(%d)", int(e))
+	}
+}
+
+func (e Encoding) constant() uint32 {
+	switch e {
+	case Bech32:
+		return bech32Const
+	case Bech32m:
+		return bech32mConst
+	default:
+		panic("unknown bech32 encoding")
+	}
+}
+
+// CharacterError reports a character that can not appear at its position,
+// it matches ErrInvalidCharacter with errors.Is
+type CharacterError struct {
+	Char     byte
+	Position int
+}
+
+func (e *CharacterError) Error() string {
+	return fmt.Sprintf("%s %q at position %d", ErrInvalidCharacter, e.Char, e.Position)
+}
+
+func (e *CharacterError) Unwrap() error {
+	return ErrInvalidCharacter
+}
+
+// ChecksumError reports a checksum matching none of the encodings.
+// Positions hints at the indexes of up to two mistyped characters
+// of the data part, it is empty when they could not be located
+type ChecksumError struct {
+	Positions []int
+}
+
+func (e *ChecksumError) Error() string {
+	if len(e.Positions) == 0 {
+		return ErrInvalidChecksum.Error()
+	}
+
+	return fmt.Sprintf("%s, check the characters at positions %v", ErrInvalidChecksum, e.Positions)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrInvalidChecksum
+}
+
+func polymodStep(chk uint32, v byte) uint32 {
+	top := chk >> 25
+	chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+	for i := 0; i < 5; i++ {
+		if (top>>i)&1 == 1 {
+			chk ^= generator[i]
+		}
+	}
+
+	return chk
+}
+
+func polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		chk = polymodStep(chk, v)
+	}
+
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+
+	return expanded
+}
+
+func checksum(hrp string, data []byte, enc Encoding) []byte {
+	values := append(hrpExpand(hrp), data...)
+	mod := polymod(append(values, make([]byte, ChecksumLength)...)) ^ enc.constant()
+
+	sum := make([]byte, ChecksumLength)
+	for i := range sum {
+		sum[i] = byte(mod>>(5*(ChecksumLength-1-i))) & 31
+	}
+
+	return sum
+}
+
+// Encode appends the checksum to the 5 bits values of data, the human
+// readable part is lowercased. The length is not limited so longer
+// formats can be produced, the caller enforces its own limit
+func Encode(hrp string, data []byte, enc Encoding) (string, error) {
+	hrp = strings.ToLower(hrp)
+	if err := checkHRP(hrp); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(data) + ChecksumLength)
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		if d > 31 {
+			return "", fmt.Errorf("%w: value %d does not fit in 5 bits", ErrInvalidData, d)
+		}
+		sb.WriteByte(charset[d])
+	}
+
+	for _, d := range checksum(hrp, data, enc) {
+		sb.WriteByte(charset[d])
+	}
+
+	return sb.String(), nil
+}
+
+// Decode parses a string of at most MaxLength characters, returning
+// the lowercase human readable part, the 5 bits values without the
+// checksum and the encoding the checksum matched
+func Decode(s string) (string, []byte, Encoding, error) {
+	return DecodeWithLimit(s, MaxLength)
+}
+
+func DecodeWithLimit(s string, limit int) (string, []byte, Encoding, error) {
+	if len(s) > limit {
+		return "", nil, 0, fmt.Errorf("%w: %d characters over the %d limit", ErrInvalidLength, len(s), limit)
+	}
+
+	hasLower, hasUpper := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 33 || c > 126 {
+			return "", nil, 0, &CharacterError{Char: c, Position: i}
+		}
+
+		hasLower = hasLower || (c >= 'a' && c <= 'z')
+		hasUpper = hasUpper || (c >= 'A' && c <= 'Z')
+	}
+
+	if hasLower && hasUpper {
+		return "", nil, 0, ErrMixedCase
+	}
+
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+ChecksumLength+1 > len(s) {
+		return "", nil, 0, ErrInvalidSeparator
+	}
+
+	hrp := s[:sep]
+	if err := checkHRP(hrp); err != nil {
+		return "", nil, 0, err
+	}
+
+	data := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		d := charsetRev[s[i]]
+		if d < 0 {
+			return "", nil, 0, &CharacterError{Char: s[i], Position: i}
+		}
+		data = append(data, byte(d))
+	}
+
+	var enc Encoding
+	switch polymod(append(hrpExpand(hrp), data...)) {
+	case bech32Const:
+		enc = Bech32
+	case bech32mConst:
+		enc = Bech32m
+	default:
+		positions := locateErrors(hrp, data)
+		for i := range positions {
+			positions[i] += sep + 1
+		}
+
+		return "", nil, 0, &ChecksumError{Positions: positions}
+	}
+
+	return hrp, data[:len(data)-ChecksumLength], enc, nil
+}
+
+func checkHRP(hrp string) error {
+	if len(hrp) == 0 || len(hrp) > MaxHRPLength {
+		return fmt.Errorf("%w: must be between 1 and %d characters", ErrInvalidHRP, MaxHRPLength)
+	}
+
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return &CharacterError{Char: hrp[i], Position: i}
+		}
+	}
+
+	return nil
+}
+
+// ConvertBits regroups fromBits wide values into toBits wide values.
+// Without padding the trailing bits must be fewer than fromBits and
+// zero, as required when decoding
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	if fromBits < 1 || fromBits > 8 || toBits < 1 || toBits > 8 {
+		panic("bit groups must be between 1 and 8 bits wide")
+	}
+
+	var (
+		acc    uint32
+		bits   uint
+		maxv   = uint32(1)<<toBits - 1
+		result = make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	)
+
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, fmt.Errorf("%w: value %d does not fit in %d bits", ErrInvalidData, v, fromBits)
+		}
+
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits {
+		return nil, fmt.Errorf("%w: more than %d bits of padding", ErrInvalidPadding, fromBits-1)
+	} else if acc<<(toBits-bits)&maxv != 0 {
+		return nil, fmt.Errorf("%w: non zero padding", ErrInvalidPadding)
+	}
+
+	return result, nil
+}
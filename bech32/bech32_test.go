@@ -0,0 +1,207 @@
+package bech32_test
+
+import (
+	"bech32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidChecksums(t *testing.T) {
+	// BIP173 and BIP350 valid checksum vectors
+	valid := map[bech32.Encoding][]string{
+		bech32.Bech32: {
+			"A12UEL5L",
+			"a12uel5l",
+			"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+			"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+			"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j",
+			"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+			"?1ezyfcl",
+		},
+		bech32.Bech32m: {
+			"A1LQFN3A",
+			"a1lqfn3a",
+			"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6",
+			"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+			"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8",
+			"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+			"?1v759aa",
+		},
+	}
+
+	for expected, vectors := range valid {
+		for _, s := range vectors {
+			hrp, data, enc, err := bech32.Decode(s)
+			require.NoError(t, err, s)
+			require.Equal(t, expected, enc, s)
+
+			encoded, err := bech32.Encode(hrp, data, enc)
+			require.NoError(t, err)
+			require.Equal(t, strings.ToLower(s), encoded)
+		}
+	}
+}
+
+func TestInvalidStrings(t *testing.T) {
+	// BIP173 and BIP350 invalid vectors
+	invalid := map[string]error{
+		"\x201nwldj5":   bech32.ErrInvalidCharacter,
+		"\x7f1axkwrx":   bech32.ErrInvalidCharacter,
+		"\x801eym55h":   bech32.ErrInvalidCharacter,
+		"\x201xj0phk":   bech32.ErrInvalidCharacter,
+		"\x7f1g6xzxy":   bech32.ErrInvalidCharacter,
+		"\x801vctc34":   bech32.ErrInvalidCharacter,
+		"pzry9x0s0muk":  bech32.ErrInvalidSeparator,
+		"1pzry9x0s0muk": bech32.ErrInvalidSeparator,
+		"x1b4n0q5v":     bech32.ErrInvalidCharacter,
+		"li1dgmt3":      bech32.ErrInvalidSeparator,
+		"de1lg7wt\xff":  bech32.ErrInvalidCharacter,
+		"A1G7SGD8":      bech32.ErrInvalidChecksum,
+		"10a06t8":       bech32.ErrInvalidSeparator,
+		"1qzzfhee":      bech32.ErrInvalidSeparator,
+		"qyrz8wqd2c9m":  bech32.ErrInvalidSeparator,
+		"1qyrz8wqd2c9m": bech32.ErrInvalidSeparator,
+		"y1b0jsk6g":     bech32.ErrInvalidCharacter,
+		"lt1igcx5c0":    bech32.ErrInvalidCharacter,
+		"in1muywd":      bech32.ErrInvalidSeparator,
+		"mm1crxm3i":     bech32.ErrInvalidCharacter,
+		"au1s5cgom":     bech32.ErrInvalidCharacter,
+		"M1VUXWEZ":      bech32.ErrInvalidChecksum,
+		"16plkw9":       bech32.ErrInvalidSeparator,
+		"1p2gdwpf":      bech32.ErrInvalidSeparator,
+		"a12UEL5L":      bech32.ErrMixedCase,
+		"A12uEL5L":      bech32.ErrMixedCase,
+
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx": bech32.ErrInvalidLength,
+		"an84characterslonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11d6pts4": bech32.ErrInvalidLength,
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e2w":                                bech32.ErrInvalidChecksum,
+		"s lit1checkupstagehandshakeupstreamerranterredcaperredp8hs2p":                                bech32.ErrInvalidCharacter,
+		"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqsqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j": bech32.ErrInvalidLength,
+	}
+
+	for s, expected := range invalid {
+		_, _, _, err := bech32.Decode(s)
+		require.ErrorIs(t, err, expected, s)
+	}
+
+	var charErr *bech32.CharacterError
+	_, _, _, err := bech32.Decode("split1cheo2y9e2w")
+	require.True(t, errors.As(err, &charErr))
+	require.Equal(t, byte('o'), charErr.Char)
+	require.Equal(t, 9, charErr.Position)
+}
+
+func TestDecodeWithLimit(t *testing.T) {
+	data := make([]byte, 200)
+	for i := range data {
+		data[i] = byte(i % 32)
+	}
+
+	s, err := bech32.Encode("sp", data, bech32.Bech32m)
+	require.NoError(t, err)
+
+	_, _, _, err = bech32.Decode(s)
+	require.ErrorIs(t, err, bech32.ErrInvalidLength)
+
+	hrp, decoded, enc, err := bech32.DecodeWithLimit(s, 1023)
+	require.NoError(t, err)
+	require.Equal(t, "sp", hrp)
+	require.Equal(t, data, decoded)
+	require.Equal(t, bech32.Bech32m, enc)
+}
+
+func TestEncodeErrors(t *testing.T) {
+	_, err := bech32.Encode("", nil, bech32.Bech32)
+	require.ErrorIs(t, err, bech32.ErrInvalidHRP)
+
+	_, err = bech32.Encode(strings.Repeat("a", 84), nil, bech32.Bech32)
+	require.ErrorIs(t, err, bech32.ErrInvalidHRP)
+
+	_, err = bech32.Encode("a b", nil, bech32.Bech32)
+	require.ErrorIs(t, err, bech32.ErrInvalidCharacter)
+
+	_, err = bech32.Encode("bc", []byte{32}, bech32.Bech32)
+	require.ErrorIs(t, err, bech32.ErrInvalidData)
+
+	s, err := bech32.Encode("BC", []byte{1, 2}, bech32.Bech32)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(s, "bc1"))
+}
+
+func TestConvertBits(t *testing.T) {
+	vectors := []struct {
+		input, output string
+		from, to      uint
+		pad           bool
+	}{
+		{"", "", 8, 5, false},
+		{"", "", 8, 5, true},
+		{"00", "00", 8, 5, false},
+		{"00", "0000", 8, 5, true},
+		{"0000", "00", 5, 8, false},
+		{"ff", "1f1c", 8, 5, true},
+		{"1f1c", "ff", 5, 8, false},
+		{"c9ca", "190705", 8, 5, false},
+	}
+
+	for _, v := range vectors {
+		input, err := hex.DecodeString(v.input)
+		require.NoError(t, err)
+
+		output, err := bech32.ConvertBits(input, v.from, v.to, v.pad)
+		require.NoError(t, err)
+		require.Equal(t, v.output, hex.EncodeToString(output))
+	}
+
+	_, err := bech32.ConvertBits([]byte{0xff}, 8, 5, false)
+	require.ErrorIs(t, err, bech32.ErrInvalidPadding)
+
+	_, err = bech32.ConvertBits([]byte{0x1f, 0x1d}, 5, 8, false)
+	require.ErrorIs(t, err, bech32.ErrInvalidPadding)
+
+	_, err = bech32.ConvertBits([]byte{0x1f, 0x1c, 0x00}, 5, 8, false)
+	require.ErrorIs(t, err, bech32.ErrInvalidPadding)
+
+	_, err = bech32.ConvertBits([]byte{0x20}, 5, 8, true)
+	require.ErrorIs(t, err, bech32.ErrInvalidData)
+}
+
+func TestLocateErrors(t *testing.T) {
+	const valid = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+
+	typo := func(s string, positions ...int) string {
+		b := []byte(s)
+		for _, p := range positions {
+			if b[p] == 'q' {
+				b[p] = 'p'
+			} else {
+				b[p] = 'q'
+			}
+		}
+		return string(b)
+	}
+
+	for _, positions := range [][]int{{3}, {10}, {41}, {4, 5}, {3, 41}, {20, 30}} {
+		_, _, _, err := bech32.Decode(typo(valid, positions...))
+
+		var checksumErr *bech32.ChecksumError
+		require.True(t, errors.As(err, &checksumErr), positions)
+		require.Equal(t, positions, checksumErr.Positions)
+	}
+
+	// typos in a bech32m string are located as well
+	const taproot = "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"
+	_, _, _, err := bech32.Decode(typo(taproot, 12, 50))
+
+	var checksumErr *bech32.ChecksumError
+	require.True(t, errors.As(err, &checksumErr))
+	require.Equal(t, []int{12, 50}, checksumErr.Positions)
+
+	// three typos are beyond what can be located
+	_, _, _, err = bech32.Decode(typo(valid, 5, 15, 25))
+	require.ErrorIs(t, err, bech32.ErrInvalidChecksum)
+}
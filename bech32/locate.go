@@ -0,0 +1,67 @@
+package bech32
+
+// locateErrors looks for up to two substitutions of the data part that
+// make the checksum valid under either encoding. The checksum is linear,
+// replacing the value at distance p from the end by v xor e changes the
+// polymod by the polymod of e followed by p zeros, starting from zero.
+// The code has a distance of 5 within BIP173 lengths so such a fix is
+// unique there, it is only a hint for longer strings
+func locateErrors(hrp string, data []byte) []int {
+	residue := polymod(append(hrpExpand(hrp), data...))
+
+	var best []int
+	for _, enc := range []Encoding{Bech32, Bech32m} {
+		positions := locate(residue^enc.constant(), len(data))
+		if positions != nil && (best == nil || len(positions) < len(best)) {
+			best = positions
+		}
+	}
+
+	return best
+}
+
+// locate returns the data indexes, in increasing order, of the values
+// to change for the polymod to change by residue
+func locate(residue uint32, n int) []int {
+	// deltas[p][e] is the change caused by e at distance p from the end
+	deltas := make([][32]uint32, n)
+	for e := 1; e < 32; e++ {
+		deltas[0][e] = uint32(e)
+	}
+
+	for p := 1; p < n; p++ {
+		for e := 1; e < 32; e++ {
+			deltas[p][e] = polymodStep(deltas[p-1][e], 0)
+		}
+	}
+
+	// the index changing the polymod by a given value
+	single := make(map[uint32]int, n*31)
+	for p := 0; p < n; p++ {
+		for e := 1; e < 32; e++ {
+			if deltas[p][e] == residue {
+				return []int{n - 1 - p}
+			}
+
+			single[deltas[p][e]] = n - 1 - p
+		}
+	}
+
+	for p := 0; p < n; p++ {
+		for e := 1; e < 32; e++ {
+			i, ok := single[residue^deltas[p][e]]
+			j := n - 1 - p
+			if !ok || i == j {
+				continue
+			}
+
+			if i > j {
+				i, j = j, i
+			}
+
+			return []int{i, j}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package bech32
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Segwit addresses encode the witness version as the first value and
+// the witness program converted to 5 bits values. Version 0 uses
+// Bech32, versions 1 to 16 use Bech32m
+
+const (
+	MaxWitnessVersion    = 16
+	MinWitnessProgramLen = 2
+	MaxWitnessProgramLen = 40
+)
+
+var (
+	ErrWrongHRP              = errors.New("unexpected segwit address human readable part")
+	ErrInvalidWitnessVersion = errors.New("invalid witness version")
+	ErrInvalidProgramLength  = errors.New("invalid witness program length")
+	ErrWrongEncoding         = errors.New("wrong checksum encoding for the witness version")
+	ErrEmptyWitnessData      = errors.New("segwit address without a witness version")
+)
+
+// EncodeSegwitAddress encodes a witness program after checking it
+// against the rules of BIP141
+func EncodeSegwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if err := checkWitnessProgram(version, program); err != nil {
+		return "", err
+	}
+
+	data, err := ConvertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(hrp, append([]byte{version}, data...), segwitEncoding(version))
+}
+
+// DecodeSegwitAddress decodes addr and checks its human readable part
+// against hrp, returning the witness version and program
+func DecodeSegwitAddress(hrp, addr string) (byte, []byte, error) {
+	decodedHRP, data, enc, err := Decode(addr)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if decodedHRP != hrp {
+		return 0, nil, fmt.Errorf("%w: expected %q, got %q", ErrWrongHRP, hrp, decodedHRP)
+	}
+
+	if len(data) == 0 {
+		return 0, nil, ErrEmptyWitnessData
+	}
+
+	version := data[0]
+	if version > MaxWitnessVersion {
+		return 0, nil, fmt.Errorf("%w: %d", ErrInvalidWitnessVersion, version)
+	}
+
+	if enc != segwitEncoding(version) {
+		return 0, nil, fmt.Errorf("%w: version %d uses %s, got %s", ErrWrongEncoding, version, segwitEncoding(version), enc)
+	}
+
+	program, err := ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := checkWitnessProgram(version, program); err != nil {
+		return 0, nil, err
+	}
+
+	return version, program, nil
+}
+
+func segwitEncoding(version byte) Encoding {
+	if version == 0 {
+		return Bech32
+	}
+
+	return Bech32m
+}
+
+func checkWitnessProgram(version byte, program []byte) error {
+	if version > MaxWitnessVersion {
+		return fmt.Errorf("%w: %d", ErrInvalidWitnessVersion, version)
+	}
+
+	if len(program) < MinWitnessProgramLen || len(program) > MaxWitnessProgramLen {
+		return fmt.Errorf("%w: %d bytes", ErrInvalidProgramLength, len(program))
+	}
+
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("%w: version 0 programs are 20 or 32 bytes, got %d", ErrInvalidProgramLength, len(program))
+	}
+
+	return nil
+}
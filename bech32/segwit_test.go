@@ -0,0 +1,114 @@
+package bech32_test
+
+import (
+	"bech32"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func scriptPubKey(version byte, program []byte) string {
+	op := version
+	if version > 0 {
+		op = 0x50 + version
+	}
+
+	return hex.EncodeToString(append([]byte{op, byte(len(program))}, program...))
+}
+
+func TestValidSegwitAddresses(t *testing.T) {
+	// BIP350 valid segwit addresses and their output scripts
+	valid := []struct {
+		address string
+		script  string
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"BC1SW50QGDZ25J", "6002751e"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", "5210751e76e8199196d454941c45d1b3a323"},
+		{"tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", "0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	}
+
+	for _, v := range valid {
+		hrp := strings.ToLower(v.address[:2])
+		version, program, err := bech32.DecodeSegwitAddress(hrp, v.address)
+		require.NoError(t, err, v.address)
+		require.Equal(t, v.script, scriptPubKey(version, program), v.address)
+
+		encoded, err := bech32.EncodeSegwitAddress(hrp, version, program)
+		require.NoError(t, err)
+		require.Equal(t, strings.ToLower(v.address), encoded)
+	}
+}
+
+func TestInvalidSegwitAddresses(t *testing.T) {
+	// BIP173 and BIP350 invalid segwit addresses, the BIP173 ones with a
+	// version above 0 now fail on their bech32 checksum first
+	invalid := []struct {
+		hrp     string
+		address string
+		err     error
+	}{
+		{"bc", "tc1qw508d6qejxtdg4y5r3zarvary0c5xw7kg3g4ty", bech32.ErrWrongHRP},
+		{"bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", bech32.ErrInvalidChecksum},
+		{"bc", "BC13W508D6QEJXTDG4Y5R3ZARVARY0C5XW7KN40WF2", bech32.ErrInvalidWitnessVersion},
+		{"bc", "bc1rw5uspcuh", bech32.ErrWrongEncoding},
+		{"bc", "bc10w508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kw5rljs90", bech32.ErrWrongEncoding},
+		{"bc", "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P", bech32.ErrInvalidProgramLength},
+		{"tb", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sL5k7", bech32.ErrMixedCase},
+		{"bc", "bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du", bech32.ErrWrongEncoding},
+		{"tb", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3pjxtptv", bech32.ErrInvalidPadding},
+		{"bc", "bc1gmk9yu", bech32.ErrEmptyWitnessData},
+
+		{"tb", "tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut", bech32.ErrWrongHRP},
+		{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", bech32.ErrWrongEncoding},
+		{"tb", "tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf", bech32.ErrWrongEncoding},
+		{"bc", "BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL", bech32.ErrWrongEncoding},
+		{"bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", bech32.ErrWrongEncoding},
+		{"tb", "tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47", bech32.ErrWrongEncoding},
+		{"bc", "bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4", bech32.ErrInvalidCharacter},
+		{"bc", "BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R", bech32.ErrInvalidWitnessVersion},
+		{"bc", "bc1pw5dgrnzv", bech32.ErrInvalidProgramLength},
+		{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav", bech32.ErrInvalidProgramLength},
+		{"tb", "tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq", bech32.ErrMixedCase},
+		{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf", bech32.ErrInvalidPadding},
+		{"tb", "tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j", bech32.ErrInvalidPadding},
+	}
+
+	for _, v := range invalid {
+		_, _, err := bech32.DecodeSegwitAddress(v.hrp, v.address)
+		require.ErrorIs(t, err, v.err, v.address)
+	}
+}
+
+func TestEncodeSegwitAddressRules(t *testing.T) {
+	program := make([]byte, 20)
+
+	_, err := bech32.EncodeSegwitAddress("bc", 17, program)
+	require.ErrorIs(t, err, bech32.ErrInvalidWitnessVersion)
+
+	_, err = bech32.EncodeSegwitAddress("bc", 0, program[:16])
+	require.ErrorIs(t, err, bech32.ErrInvalidProgramLength)
+
+	_, err = bech32.EncodeSegwitAddress("bc", 1, program[:1])
+	require.ErrorIs(t, err, bech32.ErrInvalidProgramLength)
+
+	_, err = bech32.EncodeSegwitAddress("bc", 2, make([]byte, 41))
+	require.ErrorIs(t, err, bech32.ErrInvalidProgramLength)
+
+	// any length from 2 to 40 bytes is fine from version 1
+	for _, n := range []int{2, 16, 40} {
+		addr, err := bech32.EncodeSegwitAddress("bcrt", 3, make([]byte, n))
+		require.NoError(t, err)
+
+		version, decoded, err := bech32.DecodeSegwitAddress("bcrt", addr)
+		require.NoError(t, err)
+		require.Equal(t, byte(3), version)
+		require.Len(t, decoded, n)
+	}
+}
@@ -0,0 +1,179 @@
+package bip38
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"address"
+	"base58"
+	"chaincfg"
+	"ecc"
+	"hashes"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Passphrase protected private keys as specified by BIP38. An encrypted
+// key is the Base58Check encoding of 39 bytes
+//
+//	0x01 0x42 | flag | address hash | AES(key[0:16]) | AES(key[16:32])
+//
+// where the address hash is the first 4 bytes of HASH256 of the P2PKH
+// address of the key, used both as the scrypt salt and to check the
+// passphrase on decryption. Keys created by a third party with an
+// intermediate code use the 0x01 0x43 prefix, see ecmultiply.go
+
+const (
+	encryptedKeySize = 39
+	addressHashSize  = 4
+
+	flagNonECMultiply = 0xc0
+	flagCompressed    = 0x20
+	flagLotSequence   = 0x04
+
+	prefix              = 0x01
+	prefixNonECMultiply = 0x42
+	prefixECMultiply    = 0x43
+
+	// scrypt parameters stretching the passphrase
+	scryptN     = 16384
+	scryptR     = 8
+	scryptP     = 8
+	derivedSize = 64
+)
+
+var (
+	ErrInvalidEncryptedKey = errors.New("invalid BIP38 encrypted key")
+	ErrWrongPassphrase     = errors.New("wrong passphrase")
+)
+
+// Encrypt protects key with passphrase without EC multiplication, the
+// address committed to is the P2PKH address of key on net
+func Encrypt(key *ecc.PrivateKey, compressed bool, passphrase string, net *chaincfg.Params) (string, error) {
+	addrHash := addressHash(key.PublicKey(), compressed, net)
+
+	derived, err := scrypt.Key(normalize(passphrase), addrHash, scryptN, scryptR, scryptP, derivedSize)
+	if err != nil {
+		return "", err
+	}
+
+	secret := make([]byte, 32)
+	key.Secret().FillBytes(secret)
+
+	flag := byte(flagNonECMultiply)
+	if compressed {
+		flag |= flagCompressed
+	}
+
+	payload := make([]byte, 0, encryptedKeySize)
+	payload = append(payload, prefix, prefixNonECMultiply, flag)
+	payload = append(payload, addrHash...)
+	payload = append(payload, encryptBlock(secret[:16], derived[:16], derived[32:])...)
+	payload = append(payload, encryptBlock(secret[16:], derived[16:32], derived[32:])...)
+
+	return base58.CheckEncode(payload), nil
+}
+
+// Decrypt recovers the key of either mode, net is the network of the
+// address the encrypted key commits to
+func Decrypt(encrypted, passphrase string, net *chaincfg.Params) (*ecc.WIF, error) {
+	payload, err := base58.CheckDecode(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncryptedKey, err)
+	}
+
+	if len(payload) != encryptedKeySize || payload[0] != prefix {
+		return nil, fmt.Errorf("%w: unexpected payload", ErrInvalidEncryptedKey)
+	}
+
+	switch payload[1] {
+	case prefixNonECMultiply:
+		return decryptNonEC(payload, passphrase, net)
+	case prefixECMultiply:
+		return decryptEC(payload, passphrase, net)
+	default:
+		return nil, fmt.Errorf("%w: unknown prefix %#02x", ErrInvalidEncryptedKey, payload[1])
+	}
+}
+
+func decryptNonEC(payload []byte, passphrase string, net *chaincfg.Params) (*ecc.WIF, error) {
+	flag := payload[2]
+	if flag&^flagCompressed != flagNonECMultiply {
+		return nil, fmt.Errorf("%w: invalid flag %#02x", ErrInvalidEncryptedKey, flag)
+	}
+
+	addrHash := payload[3:7]
+	derived, err := scrypt.Key(normalize(passphrase), addrHash, scryptN, scryptR, scryptP, derivedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := append(
+		decryptBlock(payload[7:23], derived[:16], derived[32:]),
+		decryptBlock(payload[23:39], derived[16:32], derived[32:])...,
+	)
+
+	return checkedKey(big.NewInt(0).SetBytes(secret), flag&flagCompressed != 0, addrHash, net)
+}
+
+// checkedKey builds the decrypted key if it hashes to the committed
+// address, a mismatch is how a wrong passphrase shows up
+func checkedKey(secret *big.Int, compressed bool, addrHash []byte, net *chaincfg.Params) (*ecc.WIF, error) {
+	if secret.Sign() == 0 || secret.Cmp(ecc.BitcoinN) >= 0 {
+		return nil, ErrWrongPassphrase
+	}
+
+	key := ecc.NewPrivateKey(secret)
+	if !bytes.Equal(addressHash(key.PublicKey(), compressed, net), addrHash) {
+		return nil, ErrWrongPassphrase
+	}
+
+	return &ecc.WIF{PrivateKey: key, Compressed: compressed, Net: net}, nil
+}
+
+func addressHash(p *ecc.Point, compressed bool, net *chaincfg.Params) []byte {
+	h := hashes.Hash256([]byte(address.NewP2PKH(p, compressed, net).String()))
+	return h[:addressHashSize]
+}
+
+// normalize returns the passphrase in Unicode NFC as BIP38 requires
+// before it is UTF-8 encoded
+func normalize(passphrase string) []byte {
+	return norm.NFC.Bytes([]byte(passphrase))
+}
+
+// encryptBlock returns AES-256(src xor mask) for a single 16 bytes block
+func encryptBlock(src, mask, key []byte) []byte {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+
+	out := make([]byte, aes.BlockSize)
+	for i := range out {
+		out[i] = src[i] ^ mask[i]
+	}
+
+	block.Encrypt(out, out)
+	return out
+}
+
+// decryptBlock reverses encryptBlock
+func decryptBlock(src, mask, key []byte) []byte {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+
+	out := make([]byte, aes.BlockSize)
+	block.Decrypt(out, src)
+	for i := range out {
+		out[i] ^= mask[i]
+	}
+
+	return out
+}
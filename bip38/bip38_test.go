@@ -0,0 +1,113 @@
+package bip38_test
+
+import (
+	"bip38"
+	"chaincfg"
+	"ecc"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonECMultiply(t *testing.T) {
+	vectors := []struct {
+		passphrase string
+		encrypted  string
+		wif        string
+		secret     string
+	}{
+		// no compression
+		{
+			"TestingOneTwoThree",
+			"6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg",
+			"5KN7MzqK5wt2TP1fQCYyHBtDrXdJuXbUzm4A9rKAteGu3Qi5CVR",
+			"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		},
+		{
+			"Satoshi",
+			"6PRNFFkZc2NZ6dJqFfhRoFNMR9Lnyj7dYGrzdgXXVMXcxoKTePPX1dWByq",
+			"5HtasZ6ofTHP6HCwTqTkLDuLQisYPah7aUnSKfC7h4hMUVw2gi5",
+			"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae",
+		},
+		{
+			"\u03d2\u0301\u0000\U00010400\U0001f4a9",
+			"6PRW5o9FLp4gJDDVqJQKJFTpMvdsSGJxMYHtHaQBF3ooa8mwD69bapcDQn",
+			"5Jajm8eQ22H3pGWLEVCXyvND8dQZhiQhoLJNKjYXk9roUFTMSZ4",
+			"",
+		},
+		// compression
+		{
+			"TestingOneTwoThree",
+			"6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+			"L44B5gGEpqEDRS9vVPz7QT35jcBG2r3CZwSwQ4fCewXAhAhqGVpP",
+			"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		},
+		{
+			"Satoshi",
+			"6PYLtMnXvfG3oJde97zRyLYFZCYizPU5T3LwgdYJz1fRhh16bU7u6PPmY7",
+			"KwYgW8gcxj1JWJXhPSu4Fqwzfhp5Yfi42mdYmMa4XqK7NJxXUSK7",
+			"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae",
+		},
+	}
+
+	for _, v := range vectors {
+		wif, err := ecc.ParseWIF(v.wif)
+		require.NoError(t, err)
+
+		if v.secret != "" {
+			secret, ok := big.NewInt(0).SetString(v.secret, 16)
+			require.True(t, ok)
+			require.Equal(t, secret, wif.Secret())
+		}
+
+		encrypted, err := bip38.Encrypt(wif.PrivateKey, wif.Compressed, v.passphrase, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.Equal(t, v.encrypted, encrypted)
+
+		decrypted, err := bip38.Decrypt(v.encrypted, v.passphrase, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.Equal(t, v.wif, decrypted.String())
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	encrypted := "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo"
+
+	_, err := bip38.Decrypt(encrypted, "TestingOneTwoThre", &chaincfg.MainNetParams)
+	require.ErrorIs(t, err, bip38.ErrWrongPassphrase)
+
+	// the address hash commits to the network
+	_, err = bip38.Decrypt(encrypted, "TestingOneTwoThree", &chaincfg.TestNet3Params)
+	require.ErrorIs(t, err, bip38.ErrWrongPassphrase)
+
+	for _, s := range []string{
+		encrypted[:len(encrypted)-1] + "p",
+		"5KN7MzqK5wt2TP1fQCYyHBtDrXdJuXbUzm4A9rKAteGu3Qi5CVR",
+		"",
+	} {
+		_, err = bip38.Decrypt(s, "TestingOneTwoThree", &chaincfg.MainNetParams)
+		require.ErrorIs(t, err, bip38.ErrInvalidEncryptedKey, s)
+	}
+}
+
+func TestPassphraseNormalization(t *testing.T) {
+	// the passphrase of the vector above composed to NFC
+	decrypted, err := bip38.Decrypt(
+		"6PRW5o9FLp4gJDDVqJQKJFTpMvdsSGJxMYHtHaQBF3ooa8mwD69bapcDQn",
+		"\u03d3\u0000\U00010400\U0001f4a9",
+		&chaincfg.MainNetParams,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "5Jajm8eQ22H3pGWLEVCXyvND8dQZhiQhoLJNKjYXk9roUFTMSZ4", decrypted.String())
+
+	key := ecc.NewPrivateKey(big.NewInt(0xc0ffee))
+	encrypted, err := bip38.Encrypt(key, true, "hunter2", &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+
+	decrypted, err = bip38.Decrypt(encrypted, "hunter2", &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	require.Equal(t, key.Secret(), decrypted.Secret())
+	require.True(t, decrypted.Compressed)
+	require.Same(t, &chaincfg.TestNet3Params, decrypted.Net)
+}
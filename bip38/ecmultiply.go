@@ -0,0 +1,352 @@
+package bip38
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"address"
+	"base58"
+	"chaincfg"
+	"ecc"
+	"hashes"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// EC multiply mode lets the owner of a passphrase hand an intermediate
+// code to a third party, which generates keys only the owner can
+// decrypt. The intermediate code carries the owner entropy and the
+// passpoint G*passfactor, the generator picks seedb and the key is
+// passfactor*factorb with factorb = HASH256(seedb). The encrypted key is
+//
+//	0x01 0x43 | flag | address hash | owner entropy | AES(seedb...)
+//
+// and the confirmation code lets the owner check the address before
+// funding it, without revealing the key to the generator
+
+const (
+	ownerEntropySize = 8
+	ownerSaltSize    = 4
+	seedSize         = 24
+	passfactorSize   = 32
+
+	intermediateSize = 8 + ownerEntropySize + 33
+	confirmationSize = 5 + 1 + addressHashSize + ownerEntropySize + 33
+
+	maxLot      = 1<<20 - 1
+	maxSequence = 1<<12 - 1
+
+	// scrypt parameters of the key derived from the passpoint
+	passpointScryptN = 1024
+	passpointScryptR = 1
+	passpointScryptP = 1
+
+	intermediateNoLotSequence = 0x53
+	intermediateLotSequence   = 0x51
+)
+
+var (
+	// the last byte tells whether the owner entropy holds a lot and sequence
+	intermediateMagic = []byte{0x2c, 0xe9, 0xb3, 0xe1, 0xff, 0x39, 0xe2}
+	confirmationMagic = []byte{0x64, 0x3b, 0xf6, 0xa8, 0x9a}
+)
+
+var (
+	ErrInvalidIntermediate = errors.New("invalid BIP38 intermediate code")
+	ErrInvalidConfirmation = errors.New("invalid BIP38 confirmation code")
+	ErrInvalidLotSequence  = errors.New("lot or sequence number out of range")
+)
+
+// LotSequence numbers the keys generated from one intermediate code, the
+// lot is below 2^20 and the sequence below 2^12
+type LotSequence struct {
+	Lot      uint32
+	Sequence uint32
+}
+
+// GeneratedKey is the output of a third party generating a key from an
+// intermediate code, it never learns the private key
+type GeneratedKey struct {
+	Encrypted    string
+	Confirmation string
+	Address      *address.PubKeyHashAddress
+}
+
+// Confirmation is what a confirmation code proves to the passphrase owner
+type Confirmation struct {
+	Address     *address.PubKeyHashAddress
+	LotSequence *LotSequence
+}
+
+// NewIntermediateCode returns a passphrase intermediate code with a
+// random owner salt, ls is embedded in the owner entropy when not nil
+func NewIntermediateCode(passphrase string, ls *LotSequence) (string, error) {
+	if ls != nil && (ls.Lot > maxLot || ls.Sequence > maxSequence) {
+		return "", fmt.Errorf("%w: lot %d sequence %d", ErrInvalidLotSequence, ls.Lot, ls.Sequence)
+	}
+
+	ownerEntropy := make([]byte, ownerEntropySize)
+
+	for {
+		if _, err := rand.Read(ownerEntropy); err != nil {
+			return "", err
+		}
+
+		if ls != nil {
+			binary.BigEndian.PutUint32(ownerEntropy[ownerSaltSize:], ls.Lot<<12|ls.Sequence)
+		}
+
+		passfactor, err := passFactor(passphrase, ownerEntropy, ls != nil)
+		if errors.Is(err, errInvalidScalar) {
+			// BIP38 asks for a different salt
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		magic := byte(intermediateNoLotSequence)
+		if ls != nil {
+			magic = intermediateLotSequence
+		}
+
+		passpoint := marshalPoint(ecc.BitcoingGenPoint.ScalarMul(passfactor))
+
+		payload := make([]byte, 0, intermediateSize)
+		payload = append(payload, intermediateMagic...)
+		payload = append(payload, magic)
+		payload = append(payload, ownerEntropy...)
+		payload = append(payload, passpoint...)
+
+		return base58.CheckEncode(payload), nil
+	}
+}
+
+// GenerateFromIntermediate creates a new encrypted key for the owner of
+// code, together with its confirmation code and address on net
+func GenerateFromIntermediate(code string, compressed bool, net *chaincfg.Params) (*GeneratedKey, error) {
+	payload, err := base58.CheckDecode(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIntermediate, err)
+	}
+
+	if len(payload) != intermediateSize || !bytes.Equal(payload[:len(intermediateMagic)], intermediateMagic) {
+		return nil, fmt.Errorf("%w: unexpected payload", ErrInvalidIntermediate)
+	}
+
+	flag := byte(0)
+	switch payload[len(intermediateMagic)] {
+	case intermediateNoLotSequence:
+	case intermediateLotSequence:
+		flag |= flagLotSequence
+	default:
+		return nil, fmt.Errorf("%w: unexpected magic", ErrInvalidIntermediate)
+	}
+
+	if compressed {
+		flag |= flagCompressed
+	}
+
+	ownerEntropy := payload[8:16]
+	passpointBytes := payload[16:]
+
+	passpoint := new(ecc.Point)
+	if err := passpoint.UnmarshalBinary(passpointBytes); err != nil || passpoint.IsInfinity() {
+		return nil, fmt.Errorf("%w: invalid passpoint", ErrInvalidIntermediate)
+	}
+
+	seedb := make([]byte, seedSize)
+	var factorb *big.Int
+	for factorb == nil {
+		if _, err := rand.Read(seedb); err != nil {
+			return nil, err
+		}
+
+		factorb, _ = scalar(hashes.Hash256(seedb))
+	}
+
+	addr := address.NewP2PKH(passpoint.ScalarMul(factorb), compressed, net)
+	addrHash := hashes.Hash256([]byte(addr.String()))
+
+	derived, err := passpointKey(passpointBytes, addrHash[:addressHashSize], ownerEntropy)
+	if err != nil {
+		return nil, err
+	}
+
+	part1 := encryptBlock(seedb[:16], derived[:16], derived[32:])
+	part2 := encryptBlock(append(part1[8:16:16], seedb[16:]...), derived[16:32], derived[32:])
+
+	key := make([]byte, 0, encryptedKeySize)
+	key = append(key, prefix, prefixECMultiply, flag)
+	key = append(key, addrHash[:addressHashSize]...)
+	key = append(key, ownerEntropy...)
+	key = append(key, part1[:8]...)
+	key = append(key, part2...)
+
+	pointb := marshalPoint(ecc.BitcoingGenPoint.ScalarMul(factorb))
+
+	confirmation := make([]byte, 0, confirmationSize)
+	confirmation = append(confirmation, confirmationMagic...)
+	confirmation = append(confirmation, flag)
+	confirmation = append(confirmation, addrHash[:addressHashSize]...)
+	confirmation = append(confirmation, ownerEntropy...)
+	confirmation = append(confirmation, pointb[0]^derived[63]&0x01)
+	confirmation = append(confirmation, encryptBlock(pointb[1:17], derived[:16], derived[32:])...)
+	confirmation = append(confirmation, encryptBlock(pointb[17:], derived[16:32], derived[32:])...)
+
+	return &GeneratedKey{
+		Encrypted:    base58.CheckEncode(key),
+		Confirmation: base58.CheckEncode(confirmation),
+		Address:      addr,
+	}, nil
+}
+
+// VerifyConfirmation checks with the passphrase that a confirmation code
+// belongs to a key the passphrase decrypts and returns its address
+func VerifyConfirmation(code, passphrase string, net *chaincfg.Params) (*Confirmation, error) {
+	payload, err := base58.CheckDecode(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfirmation, err)
+	}
+
+	if len(payload) != confirmationSize || !bytes.Equal(payload[:len(confirmationMagic)], confirmationMagic) {
+		return nil, fmt.Errorf("%w: unexpected payload", ErrInvalidConfirmation)
+	}
+
+	flag := payload[5]
+	if flag&^(flagCompressed|flagLotSequence) != 0 {
+		return nil, fmt.Errorf("%w: invalid flag %#02x", ErrInvalidConfirmation, flag)
+	}
+
+	addrHash := payload[6:10]
+	ownerEntropy := payload[10:18]
+	encryptedPointb := payload[18:]
+	lotSequence := flag&flagLotSequence != 0
+
+	passfactor, err := passFactor(passphrase, ownerEntropy, lotSequence)
+	if errors.Is(err, errInvalidScalar) {
+		return nil, ErrWrongPassphrase
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	derived, err := passpointKey(marshalPoint(ecc.BitcoingGenPoint.ScalarMul(passfactor)), addrHash, ownerEntropy)
+	if err != nil {
+		return nil, err
+	}
+
+	pointbBytes := []byte{encryptedPointb[0] ^ derived[63]&0x01}
+	pointbBytes = append(pointbBytes, decryptBlock(encryptedPointb[1:17], derived[:16], derived[32:])...)
+	pointbBytes = append(pointbBytes, decryptBlock(encryptedPointb[17:], derived[16:32], derived[32:])...)
+
+	pointb := new(ecc.Point)
+	if err := pointb.UnmarshalBinary(pointbBytes); err != nil || pointb.IsInfinity() {
+		return nil, ErrWrongPassphrase
+	}
+
+	addr := address.NewP2PKH(pointb.ScalarMul(passfactor), flag&flagCompressed != 0, net)
+	if h := hashes.Hash256([]byte(addr.String())); !bytes.Equal(h[:addressHashSize], addrHash) {
+		return nil, ErrWrongPassphrase
+	}
+
+	return &Confirmation{Address: addr, LotSequence: lotSequenceOf(ownerEntropy, lotSequence)}, nil
+}
+
+func decryptEC(payload []byte, passphrase string, net *chaincfg.Params) (*ecc.WIF, error) {
+	flag := payload[2]
+	if flag&^(flagCompressed|flagLotSequence) != 0 {
+		return nil, fmt.Errorf("%w: invalid flag %#02x", ErrInvalidEncryptedKey, flag)
+	}
+
+	addrHash := payload[3:7]
+	ownerEntropy := payload[7:15]
+
+	passfactor, err := passFactor(passphrase, ownerEntropy, flag&flagLotSequence != 0)
+	if errors.Is(err, errInvalidScalar) {
+		return nil, ErrWrongPassphrase
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	derived, err := passpointKey(marshalPoint(ecc.BitcoingGenPoint.ScalarMul(passfactor)), addrHash, ownerEntropy)
+	if err != nil {
+		return nil, err
+	}
+
+	// the second block holds the end of the first one and of seedb
+	part2 := decryptBlock(payload[23:39], derived[16:32], derived[32:])
+	part1 := append(payload[15:23:23], part2[:8]...)
+	seedb := append(decryptBlock(part1, derived[:16], derived[32:]), part2[8:]...)
+
+	factorb, err := scalar(hashes.Hash256(seedb))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	secret := big.NewInt(0).Mul(passfactor, factorb)
+	return checkedKey(secret.Mod(secret, ecc.BitcoinN), flag&flagCompressed != 0, addrHash, net)
+}
+
+var errInvalidScalar = errors.New("scalar out of range")
+
+// passFactor stretches the passphrase with the owner salt, which is the
+// whole owner entropy unless it ends with a lot and sequence number
+func passFactor(passphrase string, ownerEntropy []byte, lotSequence bool) (*big.Int, error) {
+	salt := ownerEntropy
+	if lotSequence {
+		salt = ownerEntropy[:ownerSaltSize]
+	}
+
+	prefactor, err := scrypt.Key(normalize(passphrase), salt, scryptN, scryptR, scryptP, passfactorSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lotSequence {
+		return scalar([32]byte(prefactor))
+	}
+
+	return scalar(hashes.Hash256(append(prefactor, ownerEntropy...)))
+}
+
+// passpointKey derives the AES key and masks of the EC multiply mode
+func passpointKey(passpoint, addrHash, ownerEntropy []byte) ([]byte, error) {
+	salt := append(append([]byte{}, addrHash...), ownerEntropy...)
+	return scrypt.Key(passpoint, salt, passpointScryptN, passpointScryptR, passpointScryptP, derivedSize)
+}
+
+func lotSequenceOf(ownerEntropy []byte, lotSequence bool) *LotSequence {
+	if !lotSequence {
+		return nil
+	}
+
+	n := binary.BigEndian.Uint32(ownerEntropy[ownerSaltSize:])
+	return &LotSequence{Lot: n >> 12, Sequence: n & maxSequence}
+}
+
+func scalar(b [32]byte) (*big.Int, error) {
+	s := big.NewInt(0).SetBytes(b[:])
+	if s.Sign() == 0 || s.Cmp(ecc.BitcoinN) >= 0 {
+		return nil, errInvalidScalar
+	}
+
+	return s, nil
+}
+
+func marshalPoint(p *ecc.Point) []byte {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
@@ -0,0 +1,128 @@
+package bip38_test
+
+import (
+	"bip38"
+	"chaincfg"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ecVector struct {
+	passphrase   string
+	intermediate string
+	encrypted    string
+	address      string
+	wif          string
+	confirmation string
+	lotSequence  *bip38.LotSequence
+}
+
+var ecVectors = []ecVector{
+	// no compression, no lot and sequence numbers
+	{
+		passphrase:   "TestingOneTwoThree",
+		intermediate: "passphrasepxFy57B9v8HtUsszJYKReoNDV6VHjUSGt8EVJmux9n1J3Ltf1gRxyDGXqnf9qm",
+		encrypted:    "6PfQu77ygVyJLZjfvMLyhLMQbYnu5uguoJJ4kMCLqWwPEdfpwANVS76gTX",
+		address:      "1PE6TQi6HTVNz5DLwB1LcpMBALubfuN2z2",
+		wif:          "5K4caxezwjGCGfnoPTZ8tMcJBLB7Jvyjv4xxeacadhq8nLisLR2",
+	},
+	{
+		passphrase:   "Satoshi",
+		intermediate: "passphraseoRDGAXTWzbp72eVbtUDdn1rwpgPUGjNZEc6CGBo8i5EC1FPW8wcnLdq4ThKzAS",
+		encrypted:    "6PfLGnQs6VZnrNpmVKfjotbnQuaJK4KZoPFrAjx1JMJUa1Ft8gnf5WxfKd",
+		address:      "1CqzrtZC6mXSAhoxtFwVjz8LtwLJjDYU3V",
+		wif:          "5KJ51SgxWaAYR13zd9ReMhJpwrcX47xTJh2D3fGPG9CM8vkv5sH",
+	},
+	// no compression, lot and sequence numbers
+	{
+		passphrase:   "MOLON LABE",
+		intermediate: "passphraseaB8feaLQDENqCgr4gKZpmf4VoaT6qdjJNJiv7fsKvjqavcJxvuR1hy25aTu5sX",
+		encrypted:    "6PgNBNNzDkKdhkT6uJntUXwwzQV8Rr2tZcbkDcuC9DZRsS6AtHts4Ypo1j",
+		address:      "1Jscj8ALrYu2y9TD8NrpvDBugPedmbj4Yh",
+		wif:          "5JLdxTtcTHcfYcmJsNVy1v2PMDx432JPoYcBTVVRHpPaxUrdtf8",
+		confirmation: "cfrm38V8aXBn7JWA1ESmFMUn6erxeBGZGAxJPY4e36S9QWkzZKtaVqLNMgnifETYw7BPwWC9aPD",
+		lotSequence:  &bip38.LotSequence{Lot: 263183, Sequence: 1},
+	},
+	{
+		passphrase:   "ΜΟΛΩΝ ΛΑΒΕ",
+		intermediate: "passphrased3z9rQJHSyBkNBwTRPkUGNVEVrUAcfAXDyRU1V28ie6hNFbqDwbFBvsTK7yWVK",
+		encrypted:    "6PgGWtx25kUg8QWvwuJAgorN6k9FbE25rv5dMRwu5SKMnfpfVe5mar2ngH",
+		address:      "1Lurmih3KruL4xDB5FmHof38yawNtP9oGf",
+		wif:          "5KMKKuUmAkiNbA3DazMQiLfDq47qs8MAEThm4yL8R2PhV1ov33D",
+		confirmation: "cfrm38V8G4qq2ywYEFfWLD5Cc6msj9UwsG2Mj4Z6QdGJAFQpdatZLavkgRd1i4iBMdRngDqDs51",
+		lotSequence:  &bip38.LotSequence{Lot: 806938, Sequence: 1},
+	},
+}
+
+func TestECMultiplyVectors(t *testing.T) {
+	for _, v := range ecVectors {
+		decrypted, err := bip38.Decrypt(v.encrypted, v.passphrase, &chaincfg.MainNetParams)
+		require.NoError(t, err, v.encrypted)
+		require.Equal(t, v.wif, decrypted.String())
+		require.False(t, decrypted.Compressed)
+
+		if v.confirmation == "" {
+			continue
+		}
+
+		confirmation, err := bip38.VerifyConfirmation(v.confirmation, v.passphrase, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.Equal(t, v.address, confirmation.Address.String())
+		require.Equal(t, v.lotSequence, confirmation.LotSequence)
+
+		_, err = bip38.VerifyConfirmation(v.confirmation, "wrong", &chaincfg.MainNetParams)
+		require.ErrorIs(t, err, bip38.ErrWrongPassphrase)
+	}
+}
+
+func TestGenerateFromIntermediate(t *testing.T) {
+	for i, v := range ecVectors {
+		// alternate the compression flag, each key costs three scrypt runs
+		compressed := i%2 == 1
+		generated, err := bip38.GenerateFromIntermediate(v.intermediate, compressed, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+
+		decrypted, err := bip38.Decrypt(generated.Encrypted, v.passphrase, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.Equal(t, compressed, decrypted.Compressed)
+
+		confirmation, err := bip38.VerifyConfirmation(generated.Confirmation, v.passphrase, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.Equal(t, generated.Address.String(), confirmation.Address.String())
+		require.Equal(t, v.lotSequence, confirmation.LotSequence)
+	}
+}
+
+func TestNewIntermediateCode(t *testing.T) {
+	ls := &bip38.LotSequence{Lot: 1<<20 - 1, Sequence: 7}
+	code, err := bip38.NewIntermediateCode("hunter2", ls)
+	require.NoError(t, err)
+	require.Regexp(t, "^passphrase", code)
+
+	generated, err := bip38.GenerateFromIntermediate(code, true, &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	require.Regexp(t, "^6P", generated.Encrypted)
+	require.Regexp(t, "^cfrm38", generated.Confirmation)
+
+	decrypted, err := bip38.Decrypt(generated.Encrypted, "hunter2", &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	require.Same(t, &chaincfg.TestNet3Params, decrypted.Net)
+
+	_, err = bip38.Decrypt(generated.Encrypted, "hunter3", &chaincfg.TestNet3Params)
+	require.ErrorIs(t, err, bip38.ErrWrongPassphrase)
+
+	confirmation, err := bip38.VerifyConfirmation(generated.Confirmation, "hunter2", &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	require.Equal(t, ls, confirmation.LotSequence)
+
+	_, err = bip38.NewIntermediateCode("hunter2", &bip38.LotSequence{Lot: 1 << 20})
+	require.ErrorIs(t, err, bip38.ErrInvalidLotSequence)
+	_, err = bip38.NewIntermediateCode("hunter2", &bip38.LotSequence{Sequence: 1 << 12})
+	require.ErrorIs(t, err, bip38.ErrInvalidLotSequence)
+
+	_, err = bip38.GenerateFromIntermediate("6PfQu77ygVyJLZjfvMLyhLMQbYnu5uguoJJ4kMCLqWwPEdfpwANVS76gTX", false, &chaincfg.MainNetParams)
+	require.ErrorIs(t, err, bip38.ErrInvalidIntermediate)
+	_, err = bip38.VerifyConfirmation(ecVectors[0].intermediate, "TestingOneTwoThree", &chaincfg.MainNetParams)
+	require.ErrorIs(t, err, bip38.ErrInvalidConfirmation)
+}
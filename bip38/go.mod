@@ -0,0 +1,36 @@
+module bip38
+
+go 1.23.1
+
+replace address => ../address
+
+replace base58 => ../base58
+
+replace bech32 => ../bech32
+
+replace chaincfg => ../chaincfg
+
+replace ecc => ../ecc
+
+replace hashes => ../hashes
+
+replace wire => ../wire
+
+require (
+	address v0.0.0-00010101000000-000000000000
+	base58 v0.0.0-00010101000000-000000000000
+	chaincfg v0.0.0-00010101000000-000000000000
+	ecc v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+	golang.org/x/crypto v0.31.0
+	golang.org/x/text v0.21.0
+	hashes v0.0.0-00010101000000-000000000000
+)
+
+require (
+	bech32 v0.0.0-00010101000000-000000000000 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	wire v0.0.0-00010101000000-000000000000 // indirect
+)
@@ -0,0 +1,59 @@
+package chaincfg
+
+import (
+	"encoding/binary"
+
+	"hashes"
+	"wire"
+)
+
+// HeaderSize is the size of a serialized block header
+const HeaderSize = 80
+
+// Header is a block header, hashes are kept in internal byte order,
+// the reverse of the hex strings shown by explorers and RPCs
+type Header struct {
+	Version    int32
+	PrevBlock  [32]byte
+	MerkleRoot [32]byte
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+}
+
+// Serialize returns the 80 bytes little endian encoding of h
+func (h *Header) Serialize() []byte {
+	buf := make([]byte, HeaderSize)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(h.Version))
+	copy(buf[4:], h.PrevBlock[:])
+	copy(buf[36:], h.MerkleRoot[:])
+	binary.LittleEndian.PutUint32(buf[68:], h.Timestamp)
+	binary.LittleEndian.PutUint32(buf[72:], h.Bits)
+	binary.LittleEndian.PutUint32(buf[76:], h.Nonce)
+	return buf
+}
+
+// Hash returns the double SHA256 of the header in internal byte order
+func (h *Header) Hash() [32]byte {
+	return hashes.Hash256(h.Serialize())
+}
+
+var (
+	// merkle root of the genesis coinbase shared by every network but
+	// testnet4, "The Times 03/Jan/2009 Chancellor on brink of second
+	// bailout for banks"
+	genesisMerkleRoot = hashFromString("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+
+	// testnet4 commits to a recent mainnet block and pays to a zero key
+	testNet4GenesisMerkleRoot = hashFromString("7aa0a7ae1e223414cb807e40cd57e667b718e42aaf9306db9102fe28912b7b4e")
+)
+
+// hashFromString parses a hash displayed in reversed byte order
+func hashFromString(s string) [32]byte {
+	h, err := wire.ParseHash(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return h
+}
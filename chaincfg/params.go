@@ -0,0 +1,308 @@
+package chaincfg
+
+import (
+	"math/big"
+	"time"
+
+	"hashes"
+	"wire"
+)
+
+// Params are the parameters distinguishing a network: how its addresses
+// and keys are encoded, how nodes find and recognise each other, its
+// genesis block, when soft forks became active and how difficulty works
+type Params struct {
+	Name        string
+	Magic       [4]byte
+	DefaultPort string
+	DNSSeeds    []string
+
+	GenesisHeader Header
+	GenesisHash   [32]byte
+
+	// heights from which the buried soft forks are enforced
+	BIP34Height  int32
+	BIP65Height  int32
+	BIP66Height  int32
+	CSVHeight    int32
+	SegwitHeight int32
+
+	PowLimit                 *big.Int
+	PowLimitBits             uint32
+	TargetTimespan           time.Duration
+	TargetTimePerBlock       time.Duration
+	RetargetAdjustmentFactor int64
+	SubsidyHalvingInterval   int32
+
+	// ReduceMinDifficulty allows a block at the minimum difficulty when
+	// no block was found for twice the target time per block
+	ReduceMinDifficulty bool
+	// PoWNoRetargeting keeps the difficulty of the genesis block
+	PoWNoRetargeting bool
+	// EnforceBIP94 applies the timewarp fixes of testnet4
+	EnforceBIP94 bool
+
+	// SignetChallenge is the script blocks must satisfy on signet
+	SignetChallenge []byte
+
+	PubKeyHashAddrID byte
+	ScriptHashAddrID byte
+	PrivateKeyID     byte
+	Bech32HRP        string
+
+	HDPrivateKeyID [4]byte
+	HDPublicKeyID  [4]byte
+	HDCoinType     uint32
+}
+
+var (
+	bigOne = big.NewInt(1)
+
+	// 2^224 - 1, the target of difficulty 1
+	mainPowLimit = big.NewInt(0).Sub(big.NewInt(0).Lsh(bigOne, 224), bigOne)
+	// 2^255 - 1
+	regressionPowLimit = big.NewInt(0).Sub(big.NewInt(0).Lsh(bigOne, 255), bigOne)
+	// 0x0377ae << 216
+	sigNetPowLimit = big.NewInt(0).Lsh(big.NewInt(0x0377ae), 216)
+
+	defaultSignetChallenge = []byte{
+		0x51, 0x21, 0x03, 0xad, 0x5e, 0x0e, 0xda, 0xd1, 0x8c, 0xb1, 0xf0, 0xfc,
+		0x0d, 0x28, 0xa3, 0xd4, 0xf1, 0xf3, 0xe4, 0x45, 0x64, 0x03, 0x37, 0x48,
+		0x9a, 0xbb, 0x10, 0x40, 0x4f, 0x2d, 0x1e, 0x08, 0x6b, 0xe4, 0x30, 0x21,
+		0x03, 0x59, 0xef, 0x50, 0x21, 0x96, 0x4f, 0xe2, 0x2d, 0x6f, 0x8e, 0x05,
+		0xb2, 0x46, 0x3c, 0x95, 0x40, 0xce, 0x96, 0x88, 0x3f, 0xe3, 0xb2, 0x78,
+		0x76, 0x0f, 0x04, 0x8f, 0x51, 0x89, 0xf2, 0xe6, 0xc4, 0x52, 0xae,
+	}
+)
+
+const (
+	targetTimespan     = 14 * 24 * time.Hour
+	targetTimePerBlock = 10 * time.Minute
+)
+
+var MainNetParams = Params{
+	Name:        "mainnet",
+	Magic:       [4]byte{0xf9, 0xbe, 0xb4, 0xd9},
+	DefaultPort: "8333",
+	DNSSeeds: []string{
+		"seed.bitcoin.sipa.be",
+		"dnsseed.bluematt.me",
+		"seed.bitcoinstats.com",
+		"seed.bitcoin.jonasschnelli.ch",
+		"seed.btc.petertodd.net",
+	},
+
+	GenesisHeader: Header{
+		Version:    1,
+		MerkleRoot: genesisMerkleRoot,
+		Timestamp:  1231006505,
+		Bits:       0x1d00ffff,
+		Nonce:      2083236893,
+	},
+	GenesisHash: hashFromString("000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"),
+
+	BIP34Height:  227931,
+	BIP65Height:  388381,
+	BIP66Height:  363725,
+	CSVHeight:    419328,
+	SegwitHeight: 481824,
+
+	PowLimit:                 mainPowLimit,
+	PowLimitBits:             0x1d00ffff,
+	TargetTimespan:           targetTimespan,
+	TargetTimePerBlock:       targetTimePerBlock,
+	RetargetAdjustmentFactor: 4,
+	SubsidyHalvingInterval:   210000,
+
+	PubKeyHashAddrID: 0x00,
+	ScriptHashAddrID: 0x05,
+	PrivateKeyID:     0x80,
+	Bech32HRP:        "bc",
+
+	HDPrivateKeyID: [4]byte{0x04, 0x88, 0xad, 0xe4}, // xprv
+	HDPublicKeyID:  [4]byte{0x04, 0x88, 0xb2, 0x1e}, // xpub
+	HDCoinType:     0,
+}
+
+var TestNet3Params = Params{
+	Name:        "testnet3",
+	Magic:       [4]byte{0x0b, 0x11, 0x09, 0x07},
+	DefaultPort: "18333",
+	DNSSeeds: []string{
+		"testnet-seed.bitcoin.jonasschnelli.ch",
+		"seed.tbtc.petertodd.net",
+		"testnet-seed.bluematt.me",
+	},
+
+	GenesisHeader: Header{
+		Version:    1,
+		MerkleRoot: genesisMerkleRoot,
+		Timestamp:  1296688602,
+		Bits:       0x1d00ffff,
+		Nonce:      414098458,
+	},
+	GenesisHash: hashFromString("000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943"),
+
+	BIP34Height:  21111,
+	BIP65Height:  581885,
+	BIP66Height:  330776,
+	CSVHeight:    770112,
+	SegwitHeight: 834624,
+
+	PowLimit:                 mainPowLimit,
+	PowLimitBits:             0x1d00ffff,
+	TargetTimespan:           targetTimespan,
+	TargetTimePerBlock:       targetTimePerBlock,
+	RetargetAdjustmentFactor: 4,
+	SubsidyHalvingInterval:   210000,
+	ReduceMinDifficulty:      true,
+
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	Bech32HRP:        "tb",
+
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+	HDCoinType:     1,
+}
+
+var TestNet4Params = Params{
+	Name:        "testnet4",
+	Magic:       [4]byte{0x1c, 0x16, 0x3f, 0x28},
+	DefaultPort: "48333",
+	DNSSeeds: []string{
+		"seed.testnet4.bitcoin.sprovoost.nl",
+		"seed.testnet4.wiz.biz",
+	},
+
+	GenesisHeader: Header{
+		Version:    1,
+		MerkleRoot: testNet4GenesisMerkleRoot,
+		Timestamp:  1714777860,
+		Bits:       0x1d00ffff,
+		Nonce:      393743547,
+	},
+	GenesisHash: hashFromString("00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043"),
+
+	BIP34Height:  1,
+	BIP65Height:  1,
+	BIP66Height:  1,
+	CSVHeight:    1,
+	SegwitHeight: 1,
+
+	PowLimit:                 mainPowLimit,
+	PowLimitBits:             0x1d00ffff,
+	TargetTimespan:           targetTimespan,
+	TargetTimePerBlock:       targetTimePerBlock,
+	RetargetAdjustmentFactor: 4,
+	SubsidyHalvingInterval:   210000,
+	ReduceMinDifficulty:      true,
+	EnforceBIP94:             true,
+
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	Bech32HRP:        "tb",
+
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+	HDCoinType:     1,
+}
+
+// SigNetParams are the parameters of the default signet, use
+// CustomSignetParams for other challenges
+var SigNetParams = CustomSignetParams(defaultSignetChallenge, []string{
+	"seed.signet.bitcoin.sprovoost.nl",
+})
+
+var RegressionNetParams = Params{
+	Name:        "regtest",
+	Magic:       [4]byte{0xfa, 0xbf, 0xb5, 0xda},
+	DefaultPort: "18444",
+
+	GenesisHeader: Header{
+		Version:    1,
+		MerkleRoot: genesisMerkleRoot,
+		Timestamp:  1296688602,
+		Bits:       0x207fffff,
+		Nonce:      2,
+	},
+	GenesisHash: hashFromString("0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206"),
+
+	BIP34Height:  1,
+	BIP65Height:  1,
+	BIP66Height:  1,
+	CSVHeight:    1,
+	SegwitHeight: 0,
+
+	PowLimit:                 regressionPowLimit,
+	PowLimitBits:             0x207fffff,
+	TargetTimespan:           targetTimespan,
+	TargetTimePerBlock:       targetTimePerBlock,
+	RetargetAdjustmentFactor: 4,
+	SubsidyHalvingInterval:   150,
+	ReduceMinDifficulty:      true,
+	PoWNoRetargeting:         true,
+
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	Bech32HRP:        "bcrt",
+
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+	HDCoinType:     1,
+}
+
+// CustomSignetParams returns the parameters of the signet whose blocks
+// satisfy challenge. Its magic is the first 4 bytes of the HASH256 of
+// the challenge serialized with its compact size length
+func CustomSignetParams(challenge []byte, seeds []string) Params {
+	serialized := wire.AppendCompactSize(nil, uint64(len(challenge)))
+	serialized = append(serialized, challenge...)
+
+	var magic [4]byte
+	h := hashes.Hash256(serialized)
+	copy(magic[:], h[:4])
+
+	return Params{
+		Name:        "signet",
+		Magic:       magic,
+		DefaultPort: "38333",
+		DNSSeeds:    seeds,
+
+		GenesisHeader: Header{
+			Version:    1,
+			MerkleRoot: genesisMerkleRoot,
+			Timestamp:  1598918400,
+			Bits:       0x1e0377ae,
+			Nonce:      52613770,
+		},
+		GenesisHash: hashFromString("00000008819873e925422c1ff0f99f7cc9bbb232af63a077a480a3633bee1ef6"),
+
+		BIP34Height:  1,
+		BIP65Height:  1,
+		BIP66Height:  1,
+		CSVHeight:    1,
+		SegwitHeight: 1,
+
+		PowLimit:                 sigNetPowLimit,
+		PowLimitBits:             0x1e0377ae,
+		TargetTimespan:           targetTimespan,
+		TargetTimePerBlock:       targetTimePerBlock,
+		RetargetAdjustmentFactor: 4,
+		SubsidyHalvingInterval:   210000,
+
+		SignetChallenge: append([]byte{}, challenge...),
+
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+		Bech32HRP:        "tb",
+
+		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+		HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+		HDCoinType:     1,
+	}
+}
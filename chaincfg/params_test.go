@@ -0,0 +1,88 @@
+package chaincfg_test
+
+import (
+	"chaincfg"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// compactToBig expands the compact representation of a target
+func compactToBig(bits uint32) *big.Int {
+	mantissa, exponent := int64(bits&0x007fffff), uint(bits>>24)
+	if exponent <= 3 {
+		return big.NewInt(mantissa >> (8 * (3 - exponent)))
+	}
+
+	return big.NewInt(0).Lsh(big.NewInt(mantissa), 8*(exponent-3))
+}
+
+func reversed(h [32]byte) []byte {
+	for i, j := 0, len(h)-1; i < j; i, j = i+1, j-1 {
+		h[i], h[j] = h[j], h[i]
+	}
+
+	return h[:]
+}
+
+func TestGenesisBlocks(t *testing.T) {
+	genesis := map[*chaincfg.Params]string{
+		&chaincfg.MainNetParams:       "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+		&chaincfg.TestNet3Params:      "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943",
+		&chaincfg.TestNet4Params:      "00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043",
+		&chaincfg.SigNetParams:        "00000008819873e925422c1ff0f99f7cc9bbb232af63a077a480a3633bee1ef6",
+		&chaincfg.RegressionNetParams: "0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206",
+	}
+
+	for params, hash := range genesis {
+		require.Equal(t, hash, hex.EncodeToString(reversed(params.GenesisHash)), params.Name)
+		require.Equal(t, params.GenesisHash, params.GenesisHeader.Hash(), params.Name)
+		require.Len(t, params.GenesisHeader.Serialize(), chaincfg.HeaderSize)
+
+		// the genesis block satisfies its own proof of work
+		target := compactToBig(params.GenesisHeader.Bits)
+		require.True(t, big.NewInt(0).SetBytes(reversed(params.GenesisHash)).Cmp(target) <= 0, params.Name)
+		require.True(t, target.Cmp(params.PowLimit) <= 0, params.Name)
+		require.Equal(t, params.PowLimitBits, params.GenesisHeader.Bits, params.Name)
+	}
+}
+
+func TestPrefixes(t *testing.T) {
+	main, test := &chaincfg.MainNetParams, &chaincfg.TestNet3Params
+
+	require.Equal(t, byte(0x00), main.PubKeyHashAddrID)
+	require.Equal(t, byte(0x80), main.PrivateKeyID)
+	require.Equal(t, "bc", main.Bech32HRP)
+	require.Equal(t, "0488ade4", hex.EncodeToString(main.HDPrivateKeyID[:]))
+	require.Equal(t, "f9beb4d9", hex.EncodeToString(main.Magic[:]))
+
+	// every test network shares the testnet3 encodings but regtest's hrp
+	for _, params := range []*chaincfg.Params{&chaincfg.TestNet4Params, &chaincfg.SigNetParams, &chaincfg.RegressionNetParams} {
+		require.Equal(t, test.PubKeyHashAddrID, params.PubKeyHashAddrID, params.Name)
+		require.Equal(t, test.ScriptHashAddrID, params.ScriptHashAddrID, params.Name)
+		require.Equal(t, test.PrivateKeyID, params.PrivateKeyID, params.Name)
+		require.Equal(t, test.HDPublicKeyID, params.HDPublicKeyID, params.Name)
+	}
+
+	require.Equal(t, "bcrt", chaincfg.RegressionNetParams.Bech32HRP)
+	require.Equal(t, "tb", chaincfg.SigNetParams.Bech32HRP)
+}
+
+func TestSignetMagic(t *testing.T) {
+	require.Equal(t, "0a03cf40", hex.EncodeToString(chaincfg.SigNetParams.Magic[:]))
+
+	// a 1 of 1 challenge with another key
+	challenge, err := hex.DecodeString("512102f7561d208dd9ae99bf497273e16f389bdbd6c4742ddb8e6b216e64fa2928ad8f51ae")
+	require.NoError(t, err)
+
+	custom := chaincfg.CustomSignetParams(challenge, nil)
+	require.Equal(t, challenge, custom.SignetChallenge)
+	require.NotEqual(t, chaincfg.SigNetParams.Magic, custom.Magic)
+	require.Equal(t, chaincfg.SigNetParams.GenesisHash, custom.GenesisHash)
+
+	// the challenge is copied
+	challenge[0] = 0x52
+	require.Equal(t, byte(0x51), custom.SignetChallenge[0])
+}
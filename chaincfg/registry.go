@@ -0,0 +1,74 @@
+package chaincfg
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// The registry holds the networks encoders and decoders consult, the
+// default ones are registered in that order: mainnet, testnet3,
+// testnet4, signet and regtest. Lookups return the first match, several
+// networks share their address prefixes and custom signets their name
+
+var (
+	ErrDuplicateNet = errors.New("network already registered")
+	ErrUnknownNet   = errors.New("unknown network")
+
+	registry struct {
+		sync.RWMutex
+		params []*Params
+	}
+)
+
+func init() {
+	for _, p := range []*Params{&MainNetParams, &TestNet3Params, &TestNet4Params, &SigNetParams, &RegressionNetParams} {
+		if err := Register(p); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register adds p to the registry, networks are identified by their magic
+func Register(p *Params) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, registered := range registry.params {
+		if registered.Magic == p.Magic {
+			return fmt.Errorf("%w: %s uses the magic %x", ErrDuplicateNet, registered.Name, p.Magic)
+		}
+	}
+
+	registry.params = append(registry.params, p)
+	return nil
+}
+
+// Networks returns the registered networks in registration order
+func Networks() []*Params {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return append([]*Params{}, registry.params...)
+}
+
+func ParamsForMagic(magic [4]byte) (*Params, error) {
+	return find(func(p *Params) bool { return p.Magic == magic }, fmt.Sprintf("magic %x", magic))
+}
+
+func ParamsForName(name string) (*Params, error) {
+	return find(func(p *Params) bool { return p.Name == name }, "name "+name)
+}
+
+func find(match func(*Params) bool, desc string) (*Params, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, p := range registry.params {
+		if match(p) {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownNet, desc)
+}
@@ -0,0 +1,52 @@
+package chaincfg_test
+
+import (
+	"chaincfg"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	networks := chaincfg.Networks()
+	require.GreaterOrEqual(t, len(networks), 5)
+
+	names := make([]string, 5)
+	for i, p := range networks[:5] {
+		names[i] = p.Name
+	}
+	require.Equal(t, []string{"mainnet", "testnet3", "testnet4", "signet", "regtest"}, names)
+
+	p, err := chaincfg.ParamsForMagic([4]byte{0x1c, 0x16, 0x3f, 0x28})
+	require.NoError(t, err)
+	require.Same(t, &chaincfg.TestNet4Params, p)
+
+	p, err = chaincfg.ParamsForName("regtest")
+	require.NoError(t, err)
+	require.Same(t, &chaincfg.RegressionNetParams, p)
+
+	_, err = chaincfg.ParamsForName("litecoin")
+	require.ErrorIs(t, err, chaincfg.ErrUnknownNet)
+
+	_, err = chaincfg.ParamsForMagic([4]byte{})
+	require.ErrorIs(t, err, chaincfg.ErrUnknownNet)
+
+	// networks are identified by their magic
+	copied := chaincfg.MainNetParams
+	require.ErrorIs(t, chaincfg.Register(&copied), chaincfg.ErrDuplicateNet)
+}
+
+func TestRegisterCustomSignet(t *testing.T) {
+	custom := chaincfg.CustomSignetParams([]byte{0x51}, nil)
+	require.NoError(t, chaincfg.Register(&custom))
+	require.ErrorIs(t, chaincfg.Register(&custom), chaincfg.ErrDuplicateNet)
+
+	p, err := chaincfg.ParamsForMagic(custom.Magic)
+	require.NoError(t, err)
+	require.Same(t, &custom, p)
+
+	// the default signet still comes first by name
+	p, err = chaincfg.ParamsForName("signet")
+	require.NoError(t, err)
+	require.Same(t, &chaincfg.SigNetParams, p)
+}
@@ -0,0 +1,405 @@
+package ecc
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"runtime"
+	"sync"
+)
+
+// Tooling to explore the small curves used by the tests: point orders,
+// enumeration of every point and discrete logs through baby-step
+// giant-step and Pollard's rho. Every function refuses curves whose
+// field is large enough to be cryptographically relevant, where they
+// would not finish anyway
+
+const (
+	// MaxToyFieldBits bounds the field of the curves accepted by
+	// Order, BabyStepGiantStep and PollardRho
+	MaxToyFieldBits = 40
+
+	// MaxEnumerableFieldBits bounds the field of the curves accepted
+	// by CurvePoints, which is linear in the field order
+	MaxEnumerableFieldBits = 20
+
+	rhoPartitions = 16
+
+	// walks longer than this many times the expected distance between
+	// distinguished points are assumed stuck in a cycle and restarted
+	rhoWalkMultiplier = 20
+
+	// a collision between walks that does not solve the discrete log
+	// is expected on groups that are not cyclic, give up after this many
+	rhoMaxUselessCollisions = 32
+)
+
+var (
+	ErrGroupTooLarge = errors.New("group is too large for a generic discrete log algorithm")
+	ErrFieldNotPrime = errors.New("field order must be prime")
+	ErrNoDiscreteLog = errors.New("point is not a multiple of the base point")
+	ErrOrderNotFound = errors.New("no multiple of the point order found in the hasse interval")
+
+	bigOne           = big.NewInt(1)
+	maxRhoCandidates = big.NewInt(1 << 16)
+)
+
+// Order returns the smallest n > 0 such that n * p is the point at infinity
+func (p *Point) Order() (*big.Int, error) {
+	if err := checkToyCurve(p, MaxToyFieldBits); err != nil {
+		return nil, err
+	}
+
+	if p.x == nil {
+		return big.NewInt(1), nil
+	}
+
+	// by hasse's theorem the group order lies in [q + 1 - w, q + 1 + w]
+	// with w = 2 * sqrt(q), find a multiple m of the point order in it
+	q := p.a.order
+	w := big.NewInt(0).Sqrt(big.NewInt(0).Lsh(q, 2))
+	w.Add(w, bigOne)
+
+	lo := big.NewInt(0).Add(q, bigOne)
+	lo.Sub(lo, w)
+	if lo.Sign() <= 0 {
+		lo.SetInt64(1)
+	}
+
+	hi := big.NewInt(0).Add(q, bigOne)
+	hi.Add(hi, w)
+
+	// (lo + j) * p = 0 => j * p = -(lo * p)
+	width := big.NewInt(0).Sub(hi, lo)
+	j, ok := babyStepGiantStep(p, p.ScalarMul(lo).Neg(), width.Add(width, bigOne))
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+
+	// divide m by its prime factors while it remains a multiple
+	m := j.Add(j, lo)
+	for _, f := range primeFactors(m) {
+		for {
+			quo, rem := big.NewInt(0).QuoRem(m, f, big.NewInt(0))
+			if rem.Sign() != 0 || p.ScalarMul(quo).x != nil {
+				break
+			}
+
+			m = quo
+		}
+	}
+
+	return m, nil
+}
+
+// CurvePoints enumerates every point of y ^ 2 = x ^ 3 + a * x + b,
+// starting with the point at infinity
+func CurvePoints(a, b *FieldElement) ([]*Point, error) {
+	if err := checkToyCurve(NewIdentityPoint(a, b), MaxEnumerableFieldBits); err != nil {
+		return nil, err
+	}
+
+	order := a.order
+	q := order.Int64()
+
+	// roots maps every square to its square roots
+	roots := make(map[int64][]int64)
+	for y := int64(0); y < q; y++ {
+		sq := y * y % q
+		roots[sq] = append(roots[sq], y)
+	}
+
+	points := []*Point{NewIdentityPoint(a, b)}
+	for x := int64(0); x < q; x++ {
+		xField := NewFieldElement(order, big.NewInt(x))
+		rhs := new(FieldElement).Square(xField)
+		rhs.Mul(rhs, xField).Sum(rhs, new(FieldElement).Mul(a, xField)).Sum(rhs, b)
+
+		for _, y := range roots[big.NewInt(0).Mod(rhs.num, order).Int64()] {
+			points = append(points, &Point{a: a, b: b, x: xField, y: NewFieldElement(order, big.NewInt(y))})
+		}
+	}
+
+	return points, nil
+}
+
+// BabyStepGiantStep returns the k in [0, g.Order()) such that
+// k * g = h, using O(sqrt(n)) time and memory
+func BabyStepGiantStep(g, h *Point) (*big.Int, error) {
+	n, err := g.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	k, ok := babyStepGiantStep(g, h, n)
+	if !ok {
+		return nil, ErrNoDiscreteLog
+	}
+
+	return k, nil
+}
+
+// PollardRho returns the k in [0, g.Order()) such that k * g = h. It
+// uses O(sqrt(n)) time but little memory, running parallel random walks
+// that report distinguished points until two of them collide. workers
+// defaults to the number of CPUs when not positive
+func PollardRho(g, h *Point, workers int) (*big.Int, error) {
+	n, err := g.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	if h.ScalarMul(n).x != nil {
+		return nil, ErrNoDiscreteLog
+	}
+
+	if n.Cmp(bigOne) == 0 {
+		return big.NewInt(0), nil
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// the walk adds one of these steps, chosen from the current point
+	steps := make([]*rhoState, rhoPartitions)
+	for i := range steps {
+		steps[i] = newRhoState(g, h, n)
+	}
+
+	// a point is distinguished when its low bits are zero, the
+	// walks report about n ^ 1/4 points before colliding
+	distinguishedBits := uint(n.BitLen() / 4)
+	maxWalk := rhoWalkMultiplier << distinguishedBits
+
+	var (
+		found = make(chan *rhoState)
+		done  = make(chan struct{})
+		wg    sync.WaitGroup
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				state := newRhoState(g, h, n)
+				for walked := 0; walked < maxWalk && !state.distinguished(distinguishedBits); walked++ {
+					state.step(steps[state.partition()], n)
+				}
+
+				if !state.distinguished(distinguishedBits) {
+					select {
+					case <-done:
+						return
+					default:
+						continue
+					}
+				}
+
+				select {
+				case found <- state:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+
+	seen := make(map[string]*rhoState)
+	useless := 0
+	for state := range found {
+		key := pointKey(state.point)
+		other, ok := seen[key]
+		if !ok {
+			seen[key] = state
+			continue
+		}
+
+		if k, ok := solveRhoCollision(g, h, n, state, other); ok {
+			return k, nil
+		}
+
+		if other.b.Cmp(state.b) != 0 {
+			useless++
+			if useless == rhoMaxUselessCollisions {
+				return nil, ErrNoDiscreteLog
+			}
+		}
+	}
+
+	panic("unreacheable")
+}
+
+// rhoState tracks a point of the walk as a * g + b * h
+type rhoState struct {
+	point *Point
+	a, b  *big.Int
+}
+
+func newRhoState(g, h *Point, n *big.Int) *rhoState {
+	a, b := randScalar(n), randScalar(n)
+	return &rhoState{point: g.ScalarMul(a).Add(h.ScalarMul(b)), a: a, b: b}
+}
+
+func (s *rhoState) step(other *rhoState, n *big.Int) {
+	s.point = s.point.Add(other.point)
+	s.a.Add(s.a, other.a).Mod(s.a, n)
+	s.b.Add(s.b, other.b).Mod(s.b, n)
+}
+
+func (s *rhoState) partition() int {
+	if s.point.x == nil {
+		return 0
+	}
+
+	x := big.NewInt(0).Mod(s.point.x.num, s.point.x.order)
+	return int(big.NewInt(0).Mod(x, big.NewInt(rhoPartitions)).Int64())
+}
+
+func (s *rhoState) distinguished(bits uint) bool {
+	if s.point.x == nil {
+		return true
+	}
+
+	x := big.NewInt(0).Mod(s.point.x.num, s.point.x.order)
+	return x.TrailingZeroBits() >= bits || x.Sign() == 0
+}
+
+// solveRhoCollision solves a1 + b1 * k = a2 + b2 * k (mod n), the
+// congruence has gcd(b1 - b2, n) candidates when n is not prime
+func solveRhoCollision(g, h *Point, n *big.Int, s1, s2 *rhoState) (*big.Int, bool) {
+	db := big.NewInt(0).Sub(s1.b, s2.b)
+	db.Mod(db, n)
+	da := big.NewInt(0).Sub(s2.a, s1.a)
+	da.Mod(da, n)
+
+	d := big.NewInt(0).GCD(nil, nil, db, n)
+	if db.Sign() == 0 || d.Cmp(maxRhoCandidates) > 0 {
+		return nil, false
+	}
+
+	if big.NewInt(0).Mod(da, d).Sign() != 0 {
+		return nil, false
+	}
+
+	reduced := big.NewInt(0).Div(n, d)
+	k := big.NewInt(0).ModInverse(big.NewInt(0).Div(db, d), reduced)
+	if k == nil {
+		// reduced == 1, the only candidate is zero
+		k = big.NewInt(0)
+	}
+	k.Mul(k, big.NewInt(0).Div(da, d)).Mod(k, reduced)
+
+	for i := int64(0); i < d.Int64(); i++ {
+		if pointKey(g.ScalarMul(k)) == pointKey(h) {
+			return k, true
+		}
+
+		k.Add(k, reduced)
+	}
+
+	return nil, false
+}
+
+// babyStepGiantStep finds the smallest j in [0, width) with j * base = target
+func babyStepGiantStep(base, target *Point, width *big.Int) (*big.Int, bool) {
+	s := big.NewInt(0).Sqrt(width)
+	if big.NewInt(0).Mul(s, s).Cmp(width) < 0 {
+		s.Add(s, bigOne)
+	}
+
+	// baby steps: i * base for i in [0, s)
+	table := make(map[string]int64, s.Int64())
+	curr := NewIdentityPoint(base.a, base.b)
+	for i := int64(0); i < s.Int64(); i++ {
+		key := pointKey(curr)
+		if _, ok := table[key]; !ok {
+			table[key] = i
+		}
+
+		curr = curr.Add(base)
+	}
+
+	// giant steps: target - t * s * base
+	giant := base.ScalarMul(s).Neg()
+	curr = target
+	for t := int64(0); t <= s.Int64(); t++ {
+		if i, ok := table[pointKey(curr)]; ok {
+			j := big.NewInt(0).Mul(big.NewInt(t), s)
+			j.Add(j, big.NewInt(i))
+			if j.Cmp(width) < 0 {
+				return j, true
+			}
+		}
+
+		curr = curr.Add(giant)
+	}
+
+	return nil, false
+}
+
+func checkToyCurve(p *Point, maxBits int) error {
+	order := p.a.order
+	if order.BitLen() > maxBits {
+		return ErrGroupTooLarge
+	}
+
+	if !order.ProbablyPrime(20) {
+		return ErrFieldNotPrime
+	}
+
+	return nil
+}
+
+// primeFactors returns the distinct prime factors of m by trial division
+func primeFactors(m *big.Int) []*big.Int {
+	var (
+		factors []*big.Int
+		rest    = big.NewInt(0).Set(m)
+		rem     = big.NewInt(0)
+	)
+
+	for f := big.NewInt(2); big.NewInt(0).Mul(f, f).Cmp(rest) <= 0; f.Add(f, bigOne) {
+		quo, _ := big.NewInt(0).QuoRem(rest, f, rem)
+		if rem.Sign() != 0 {
+			continue
+		}
+
+		factors = append(factors, big.NewInt(0).Set(f))
+		for rem.Sign() == 0 {
+			rest = quo
+			quo, _ = big.NewInt(0).QuoRem(rest, f, rem)
+		}
+	}
+
+	if rest.Cmp(bigOne) > 0 {
+		factors = append(factors, rest)
+	}
+
+	return factors
+}
+
+// pointKey identifies a point of a single curve, used as map key
+func pointKey(p *Point) string {
+	if p.IsInfinity() {
+		return ""
+	}
+
+	return p.X().Text(16) + "," + p.Y().Text(16)
+}
+
+func randScalar(n *big.Int) *big.Int {
+	k, err := rand.Int(rand.Reader, n)
+	if err != nil {
+		panic("failed to generate a random scalar")
+	}
+
+	return k
+}
@@ -0,0 +1,157 @@
+package ecc_test
+
+import (
+	"ecc"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func toyCurve(order, a, b int64) (*ecc.FieldElement, *ecc.FieldElement) {
+	prime := big.NewInt(order)
+	return ecc.NewFieldElement(prime, big.NewInt(a)), ecc.NewFieldElement(prime, big.NewInt(b))
+}
+
+func toyPoint(order, x, y int64, a, b *ecc.FieldElement) *ecc.Point {
+	prime := big.NewInt(order)
+	return ecc.NewPoint(ecc.NewFieldElement(prime, big.NewInt(x)), ecc.NewFieldElement(prime, big.NewInt(y)), a, b)
+}
+
+// naiveOrder adds p to itself until reaching the point at infinity
+func naiveOrder(p *ecc.Point) int64 {
+	n, curr := int64(1), p
+	for !curr.IsInfinity() {
+		curr = curr.Add(p)
+		n++
+	}
+
+	return n
+}
+
+func TestPointOrder(t *testing.T) {
+	a, b := toyCurve(223, 0, 7)
+
+	// the examples from Programming Bitcoin
+	orders := map[[2]int64]int64{
+		{15, 86}:   7,
+		{47, 71}:   21,
+		{192, 105}: 0,
+		{17, 56}:   0,
+	}
+
+	for xy, expected := range orders {
+		p := toyPoint(223, xy[0], xy[1], a, b)
+		if expected == 0 {
+			expected = naiveOrder(p)
+		}
+
+		order, err := p.Order()
+		require.NoError(t, err)
+		require.Equal(t, expected, order.Int64(), "point (%d, %d)", xy[0], xy[1])
+	}
+
+	order, err := ecc.NewIdentityPoint(a, b).Order()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), order.Int64())
+
+	_, err = ecc.BitcoingGenPoint.Order()
+	require.ErrorIs(t, err, ecc.ErrGroupTooLarge)
+
+	// the field of order 57 is not prime
+	a57, b57 := toyCurve(57, 0, 7)
+	_, err = ecc.NewIdentityPoint(a57, b57).Order()
+	require.ErrorIs(t, err, ecc.ErrFieldNotPrime)
+}
+
+func TestCurvePoints(t *testing.T) {
+	for _, curve := range [][3]int64{{223, 0, 7}, {233, 5, 7}} {
+		a, b := toyCurve(curve[0], curve[1], curve[2])
+
+		points, err := ecc.CurvePoints(a, b)
+		require.NoError(t, err)
+
+		groupOrder := int64(len(points))
+		seen := make(map[string]bool)
+		for i, p := range points {
+			require.False(t, seen[p.String()])
+			seen[p.String()] = true
+
+			// the order of every point divides the group order
+			order, err := p.Order()
+			require.NoError(t, err)
+			require.Zero(t, groupOrder%order.Int64(), "point %d of curve %v", i, curve)
+		}
+
+		// hasse's bound
+		require.LessOrEqual(t, (groupOrder-curve[0]-1)*(groupOrder-curve[0]-1), 4*curve[0])
+	}
+
+	// the sample points of the tests are in the list
+	a, b := toyCurve(233, 5, 7)
+	points, err := ecc.CurvePoints(a, b)
+	require.NoError(t, err)
+	require.Contains(t, points, toyPoint(233, 18, 77, a, b))
+
+	_, err = ecc.CurvePoints(ecc.S256Field(big.NewInt(0)), ecc.S256Field(big.NewInt(7)))
+	require.ErrorIs(t, err, ecc.ErrGroupTooLarge)
+}
+
+func TestDiscreteLog(t *testing.T) {
+	a, b := toyCurve(223, 0, 7)
+	g := toyPoint(223, 47, 71, a, b)
+
+	for k := int64(0); k < 21; k++ {
+		h := g.ScalarMul(big.NewInt(k))
+
+		bsgs, err := ecc.BabyStepGiantStep(g, h)
+		require.NoError(t, err)
+		require.Equal(t, k, bsgs.Int64())
+
+		rho, err := ecc.PollardRho(g, h, 4)
+		require.NoError(t, err)
+		require.Equal(t, k, rho.Int64())
+	}
+
+	// (15, 86) generates a subgroup of order 7 which does not contain (47, 71)
+	small := toyPoint(223, 15, 86, a, b)
+	_, err := ecc.BabyStepGiantStep(small, g)
+	require.ErrorIs(t, err, ecc.ErrNoDiscreteLog)
+	_, err = ecc.PollardRho(small, g, 2)
+	require.ErrorIs(t, err, ecc.ErrNoDiscreteLog)
+
+	_, err = ecc.PollardRho(ecc.BitcoingGenPoint, ecc.BitcoingGenPoint, 1)
+	require.ErrorIs(t, err, ecc.ErrGroupTooLarge)
+}
+
+func TestDiscreteLogLargerCurve(t *testing.T) {
+	// y ^ 2 = x ^ 3 + 7 over a 31 bits prime field
+	order := int64(2147483647)
+	a, b := toyCurve(order, 0, 7)
+
+	x := ecc.NewFieldElement(big.NewInt(order), big.NewInt(1))
+	var g *ecc.Point
+	for g == nil {
+		x = x.Add(ecc.NewFieldElement(big.NewInt(order), big.NewInt(1)))
+		rhs := x.Power(big.NewInt(3)).Add(b)
+		if rhs.Power(big.NewInt((order - 1) / 2)).EqualTo(ecc.NewFieldElement(big.NewInt(order), big.NewInt(1))) {
+			// p = 3 mod 4 so the square root is rhs ^ ((p + 1) / 4)
+			g = ecc.NewPoint(x, rhs.Power(big.NewInt((order+1)/4)), a, b)
+		}
+	}
+
+	n, err := g.Order()
+	require.NoError(t, err)
+	require.Equal(t, ecc.NewIdentityPoint(a, b), g.ScalarMul(n))
+
+	k := big.NewInt(0).Div(n, big.NewInt(3))
+	h := g.ScalarMul(k)
+
+	rho, err := ecc.PollardRho(g, h, 0)
+	require.NoError(t, err)
+	require.Equal(t, k, rho)
+
+	bsgs, err := ecc.BabyStepGiantStep(g, h)
+	require.NoError(t, err)
+	require.Equal(t, k, bsgs)
+}
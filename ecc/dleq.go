@@ -0,0 +1,205 @@
+package ecc
+
+import (
+	"errors"
+	"math/big"
+
+	"hashes"
+)
+
+// Proofs of knowledge of discrete logarithms. A DLEQ proof, as specified
+// by BIP374, shows that A = a * G and C = a * B share the same a without
+// revealing it. A DLog proof is the single base version of it, a Schnorr
+// proof that the prover knows a for A = a * G. Both proofs are encoded as
+// the 32 bytes challenge e followed by the 32 bytes response s
+
+const DLEQProofSize = 64
+
+var (
+	ErrInvalidAuxRand = errors.New("auxiliary randomness must be 32 bytes long")
+	ErrInvalidMessage = errors.New("message must be empty or 32 bytes long")
+	ErrInfinity       = errors.New("point cannot be the point at infinity")
+	ErrZeroNonce      = errors.New("derived nonce is zero")
+
+	dleqAuxTag       = "BIP0374/aux"
+	dleqNonceTag     = "BIP0374/nonce"
+	dleqChallengeTag = "BIP0374/challenge"
+
+	dlogAuxTag       = "ecc/dlog/aux"
+	dlogNonceTag     = "ecc/dlog/nonce"
+	dlogChallengeTag = "ecc/dlog/challenge"
+)
+
+// DLEQProof proves that the public key a * g and c = a * b share the
+// same secret. aux must be 32 bytes of fresh randomness, g defaults to
+// the secp256k1 generator and msg is optional
+func (p *PrivateKey) DLEQProof(b *Point, aux []byte, g *Point, msg []byte) ([]byte, error) {
+	if g == nil {
+		g = BitcoingGenPoint
+	}
+
+	if b.x == nil || g.x == nil {
+		return nil, ErrInfinity
+	}
+
+	a, c := g.ScalarMul(p.secret), b.ScalarMul(p.secret)
+
+	k, err := proofNonce(p.secret, aux, msg, dleqAuxTag, dleqNonceTag, a, c)
+	if err != nil {
+		return nil, err
+	}
+
+	r1, r2 := g.ScalarMul(k), b.ScalarMul(k)
+	e := proofChallenge(dleqChallengeTag, msg, a, b, c, g, r1, r2)
+	proof := proofResponse(k, e, p.secret)
+
+	if !VerifyDLEQProof(a, b, c, proof, g, msg) {
+		return nil, errors.New("generated an invalid proof")
+	}
+
+	return proof, nil
+}
+
+// VerifyDLEQProof checks that a = x * g and c = x * b for the same x
+func VerifyDLEQProof(a, b, c *Point, proof []byte, g *Point, msg []byte) bool {
+	if g == nil {
+		g = BitcoingGenPoint
+	}
+
+	if a.x == nil || b.x == nil || c.x == nil || g.x == nil {
+		return false
+	}
+
+	e, s, ok := parseProof(proof, msg)
+	if !ok {
+		return false
+	}
+
+	// R1 = s * G - e * A
+	// R2 = s * B - e * C
+	minusE := big.NewInt(0).Mod(big.NewInt(0).Neg(e), BitcoinN)
+	r1 := g.ScalarMul(s).Add(a.ScalarMul(minusE))
+	r2 := b.ScalarMul(s).Add(c.ScalarMul(minusE))
+	if r1.x == nil || r2.x == nil {
+		return false
+	}
+
+	return e.Cmp(proofChallenge(dleqChallengeTag, msg, a, b, c, g, r1, r2)) == 0
+}
+
+// DLogProof proves the knowledge of the secret behind a * g, the
+// arguments follow the same rules of DLEQProof
+func (p *PrivateKey) DLogProof(g *Point, aux, msg []byte) ([]byte, error) {
+	if g == nil {
+		g = BitcoingGenPoint
+	}
+
+	if g.x == nil {
+		return nil, ErrInfinity
+	}
+
+	a := g.ScalarMul(p.secret)
+
+	k, err := proofNonce(p.secret, aux, msg, dlogAuxTag, dlogNonceTag, a)
+	if err != nil {
+		return nil, err
+	}
+
+	e := proofChallenge(dlogChallengeTag, msg, a, g, g.ScalarMul(k))
+	return proofResponse(k, e, p.secret), nil
+}
+
+// VerifyDLogProof checks that the prover knows x such that a = x * g
+func VerifyDLogProof(a *Point, proof []byte, g *Point, msg []byte) bool {
+	if g == nil {
+		g = BitcoingGenPoint
+	}
+
+	if a.x == nil || g.x == nil {
+		return false
+	}
+
+	e, s, ok := parseProof(proof, msg)
+	if !ok {
+		return false
+	}
+
+	// R = s * G - e * A
+	r := g.ScalarMul(s).Add(a.ScalarMul(big.NewInt(0).Mod(big.NewInt(0).Neg(e), BitcoinN)))
+	if r.x == nil {
+		return false
+	}
+
+	return e.Cmp(proofChallenge(dlogChallengeTag, msg, a, g, r)) == 0
+}
+
+// proofNonce derives k from the secret masked by the auxiliary
+// randomness, the compressed points and the message
+func proofNonce(secret *big.Int, aux, msg []byte, auxTag, nonceTag string, points ...*Point) (*big.Int, error) {
+	if secret.Sign() <= 0 || secret.Cmp(BitcoinN) >= 0 {
+		return nil, errors.New("secret must be in the range [1, n)")
+	}
+
+	if len(aux) != 32 {
+		return nil, ErrInvalidAuxRand
+	}
+
+	if len(msg) != 0 && len(msg) != 32 {
+		return nil, ErrInvalidMessage
+	}
+
+	t := make([]byte, 32)
+	secret.FillBytes(t)
+	auxHash := hashes.TaggedHash(auxTag, aux)
+	for i := range t {
+		t[i] ^= auxHash[i]
+	}
+
+	parts := [][]byte{t}
+	for _, point := range points {
+		parts = append(parts, point.compressedSec())
+	}
+
+	rand := hashes.TaggedHash(nonceTag, append(parts, msg)...)
+	k := big.NewInt(0).Mod(big.NewInt(0).SetBytes(rand[:]), BitcoinN)
+	if k.Sign() == 0 {
+		return nil, ErrZeroNonce
+	}
+
+	return k, nil
+}
+
+func proofChallenge(tag string, msg []byte, points ...*Point) *big.Int {
+	parts := make([][]byte, 0, len(points)+1)
+	for _, point := range points {
+		parts = append(parts, point.compressedSec())
+	}
+
+	e := hashes.TaggedHash(tag, append(parts, msg)...)
+	return big.NewInt(0).SetBytes(e[:])
+}
+
+// proofResponse encodes e and s = k + e * a
+func proofResponse(k, e, a *big.Int) []byte {
+	s := big.NewInt(0).Mul(e, a)
+	s.Add(s, k).Mod(s, BitcoinN)
+
+	proof := make([]byte, DLEQProofSize)
+	e.FillBytes(proof[:32])
+	s.FillBytes(proof[32:])
+	return proof
+}
+
+func parseProof(proof, msg []byte) (e, s *big.Int, ok bool) {
+	if len(proof) != DLEQProofSize || (len(msg) != 0 && len(msg) != 32) {
+		return nil, nil, false
+	}
+
+	e = big.NewInt(0).SetBytes(proof[:32])
+	s = big.NewInt(0).SetBytes(proof[32:])
+	if s.Cmp(BitcoinN) >= 0 {
+		return nil, nil, false
+	}
+
+	return e, s, true
+}
@@ -0,0 +1,121 @@
+package ecc_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"ecc"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDLEQProof(t *testing.T) {
+	a := ecc.NewPrivateKey(big.NewInt(12345))
+	b := ecc.NewPrivateKey(big.NewInt(4444)).PublicKey()
+	c := b.ScalarMul(big.NewInt(12345))
+
+	aux := bytes.Repeat([]byte{0x01}, 32)
+	msg := sha256.Sum256([]byte("message"))
+
+	proof, err := a.DLEQProof(b, aux, nil, msg[:])
+	require.NoError(t, err)
+	require.Len(t, proof, ecc.DLEQProofSize)
+
+	require.True(t, ecc.VerifyDLEQProof(a.PublicKey(), b, c, proof, nil, msg[:]))
+
+	// the proof is bound to the message
+	require.False(t, ecc.VerifyDLEQProof(a.PublicKey(), b, c, proof, nil, nil))
+
+	// c must share the secret of a
+	wrongC := b.ScalarMul(big.NewInt(12346))
+	require.False(t, ecc.VerifyDLEQProof(a.PublicKey(), b, wrongC, proof, nil, msg[:]))
+
+	tampered := append([]byte{}, proof...)
+	tampered[63] ^= 0x01
+	require.False(t, ecc.VerifyDLEQProof(a.PublicKey(), b, c, tampered, nil, msg[:]))
+
+	// a custom generator
+	g := ecc.NewPrivateKey(big.NewInt(777)).PublicKey()
+	proof, err = a.DLEQProof(b, aux, g, nil)
+	require.NoError(t, err)
+	require.True(t, ecc.VerifyDLEQProof(g.ScalarMul(big.NewInt(12345)), b, c, proof, g, nil))
+	require.False(t, ecc.VerifyDLEQProof(a.PublicKey(), b, c, proof, nil, nil))
+
+	_, err = a.DLEQProof(b, aux[:31], nil, nil)
+	require.ErrorIs(t, err, ecc.ErrInvalidAuxRand)
+
+	_, err = a.DLEQProof(b, aux, nil, msg[:31])
+	require.ErrorIs(t, err, ecc.ErrInvalidMessage)
+
+	_, err = a.DLEQProof(ecc.S256Point(nil, nil), aux, nil, nil)
+	require.ErrorIs(t, err, ecc.ErrInfinity)
+}
+
+func TestVerifyDLEQProof(t *testing.T) {
+	a := big.NewInt(12345)
+	b := ecc.NewPrivateKey(big.NewInt(4444)).PublicKey()
+	bigA, c := ecc.BitcoingGenPoint.ScalarMul(a), b.ScalarMul(a)
+	infinity := ecc.S256Point(nil, nil)
+
+	msg := sha256.Sum256([]byte("message"))
+	proof, err := ecc.NewPrivateKey(a).DLEQProof(b, make([]byte, 32), nil, msg[:])
+	require.NoError(t, err)
+
+	flip := func(i int) []byte {
+		tampered := append([]byte{}, proof...)
+		tampered[i] ^= 0x01
+		return tampered
+	}
+
+	// s = n does not wrap around to 0
+	sOverflow := append(append([]byte{}, proof[:32]...), ecc.BitcoinN.FillBytes(make([]byte, 32))...)
+
+	cases := []struct {
+		name    string
+		a, b, c *ecc.Point
+		g       *ecc.Point
+		proof   []byte
+		msg     []byte
+		valid   bool
+	}{
+		{name: "valid", a: bigA, b: b, c: c, proof: proof, msg: msg[:], valid: true},
+		{name: "explicit generator", a: bigA, b: b, c: c, g: ecc.BitcoingGenPoint, proof: proof, msg: msg[:], valid: true},
+		{name: "missing message", a: bigA, b: b, c: c, proof: proof},
+		{name: "wrong message", a: bigA, b: b, c: c, proof: proof, msg: make([]byte, 32)},
+		{name: "short message", a: bigA, b: b, c: c, proof: proof, msg: msg[:31]},
+		{name: "swapped a and c", a: c, b: b, c: bigA, proof: proof, msg: msg[:]},
+		{name: "wrong c", a: bigA, b: b, c: c.Neg(), proof: proof, msg: msg[:]},
+		{name: "wrong generator", a: bigA, b: b, c: c, g: b, proof: proof, msg: msg[:]},
+		{name: "e changed", a: bigA, b: b, c: c, proof: flip(0), msg: msg[:]},
+		{name: "s changed", a: bigA, b: b, c: c, proof: flip(63), msg: msg[:]},
+		{name: "s not lower than n", a: bigA, b: b, c: c, proof: sOverflow, msg: msg[:]},
+		{name: "short proof", a: bigA, b: b, c: c, proof: proof[:63], msg: msg[:]},
+		{name: "a at infinity", a: infinity, b: b, c: c, proof: proof, msg: msg[:]},
+		{name: "b at infinity", a: bigA, b: infinity, c: c, proof: proof, msg: msg[:]},
+		{name: "c at infinity", a: bigA, b: b, c: infinity, proof: proof, msg: msg[:]},
+		{name: "g at infinity", a: bigA, b: b, c: c, g: infinity, proof: proof, msg: msg[:]},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.valid, ecc.VerifyDLEQProof(tc.a, tc.b, tc.c, tc.proof, tc.g, tc.msg))
+		})
+	}
+}
+
+func TestDLogProof(t *testing.T) {
+	a := ecc.NewPrivateKey(big.NewInt(12345))
+	aux := bytes.Repeat([]byte{0x02}, 32)
+
+	proof, err := a.DLogProof(nil, aux, nil)
+	require.NoError(t, err)
+	require.True(t, ecc.VerifyDLogProof(a.PublicKey(), proof, nil, nil))
+
+	other := ecc.NewPrivateKey(big.NewInt(4444)).PublicKey()
+	require.False(t, ecc.VerifyDLogProof(other, proof, nil, nil))
+	require.False(t, ecc.VerifyDLogProof(a.PublicKey(), proof[:63], nil, nil))
+
+	// a dlog proof cannot be replayed as a proof over another generator
+	require.False(t, ecc.VerifyDLogProof(a.PublicKey(), proof, other, nil))
+}
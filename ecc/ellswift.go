@@ -0,0 +1,238 @@
+package ecc
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"hashes"
+)
+
+// ElligatorSwift encoding of public keys as specified by BIP324. A point is
+// represented by two field elements (u, t), 64 bytes in total, that are
+// indistinguishable from uniformly random bytes. Only the x coordinate
+// survives the round trip, the decoded point always has an even y
+
+const EllSwiftSize = 64
+
+var (
+	ErrInvalidEllSwift = errors.New("ellswift encoding must be 64 bytes long")
+
+	// sqrt(-3) mod p
+	ellSwiftC = hexToS256Field("0a2d2ba93507f1df233770c2a797962cc61f6d15da14ecd47d8d27ae1cd5f852")
+
+	ellSwiftECDHTag = "bip324_ellswift_xonly_ecdh"
+)
+
+// XSwiftEC maps any pair of field elements (u, t) to
+// the x coordinate of a point on the curve
+func XSwiftEC(u, t *FieldElement) *FieldElement {
+	var (
+		one   = S256Field(big.NewInt(1))
+		seven = S256Field(big.NewInt(7))
+	)
+
+	if u.num.Sign() == 0 {
+		u = one
+	}
+
+	if t.num.Sign() == 0 {
+		t = one
+	}
+
+	// u ^ 3 + 7
+	gu := new(FieldElement).Square(u)
+	gu.Mul(gu, u).Sum(gu, seven)
+
+	// t = 2 * t if u ^ 3 + t ^ 2 + 7 == 0
+	tPwr2 := new(FieldElement).Square(t)
+	if new(FieldElement).Sum(gu, tPwr2).num.Sign() == 0 {
+		t = new(FieldElement).Sum(t, t)
+		tPwr2.Square(t)
+	}
+
+	// X = (u ^ 3 + 7 - t ^ 2) / (2 * t)
+	// Y = (X + t) / (c * u)
+	bigX := new(FieldElement).Sub(gu, tPwr2)
+	bigX.Div(bigX, new(FieldElement).Sum(t, t))
+	bigY := new(FieldElement).Sum(bigX, t)
+	bigY.Div(bigY, new(FieldElement).Mul(ellSwiftC, u))
+
+	// the first of u + 4 * Y ^ 2, (-X / Y - u) / 2 and (X / Y - u) / 2
+	// that lies on the curve, one of them always does
+	x := new(FieldElement).Square(bigY)
+	x.MulInt(x, big.NewInt(4)).Sum(x, u)
+	if isXOnCurve(x) {
+		return x
+	}
+
+	xDivY := new(FieldElement).Div(bigX, bigY)
+	half := S256Field(big.NewInt(2)).Inverse()
+
+	x.Neg(xDivY).Sub(x, u).Mul(x, half)
+	if isXOnCurve(x) {
+		return x
+	}
+
+	return x.Sub(xDivY, u).Mul(x, half)
+}
+
+// XSwiftECInv finds a t such that XSwiftEC(u, t) == x for one of the
+// eight cases in [0, 8), nil is returned when that case has no solution
+func XSwiftECInv(u, x *FieldElement, c int) *FieldElement {
+	var (
+		v     = new(FieldElement)
+		s     = new(FieldElement)
+		seven = S256Field(big.NewInt(7))
+		half  = S256Field(big.NewInt(2)).Inverse()
+	)
+
+	// u ^ 3 + 7
+	gu := new(FieldElement).Square(u)
+	gu.Mul(gu, u).Sum(gu, seven)
+
+	if c&2 == 0 {
+		// there is nothing to invert when -x - u is on the curve
+		negXU := new(FieldElement).Neg(x)
+		if isXOnCurve(negXU.Sub(negXU, u)) {
+			return nil
+		}
+
+		// s = -(u ^ 3 + 7) / (u ^ 2 + u * v + v ^ 2)
+		v.Set(x)
+		den := new(FieldElement).Square(u)
+		den.Sum(den, new(FieldElement).Mul(u, v)).Sum(den, new(FieldElement).Square(v))
+		s.Neg(gu).Div(s, den)
+	} else {
+		s.Sub(x, u)
+		if s.num.Sign() == 0 {
+			return nil
+		}
+
+		// r = sqrt(-s * (4 * (u ^ 3 + 7) + 3 * s * u ^ 2))
+		rPwr2 := new(FieldElement).Square(u)
+		rPwr2.Mul(rPwr2, s).MulInt(rPwr2, big.NewInt(3))
+		rPwr2.Sum(rPwr2, new(FieldElement).MulInt(gu, big.NewInt(4))).Mul(rPwr2, s).Neg(rPwr2)
+
+		r, ok := sqrt(rPwr2)
+		if !ok || (c&1 == 1 && r.num.Sign() == 0) {
+			return nil
+		}
+
+		// v = (r / s - u) / 2
+		v.Div(r, s).Sub(v, u).Mul(v, half)
+	}
+
+	w, ok := sqrt(s)
+	if !ok {
+		return nil
+	}
+
+	// u * (1 - c) / 2 + v for the even cases, u * (1 + c) / 2 + v otherwise
+	t := new(FieldElement)
+	if c&1 == 0 {
+		t.Sub(S256Field(big.NewInt(1)), ellSwiftC)
+	} else {
+		t.Sum(S256Field(big.NewInt(1)), ellSwiftC)
+	}
+	t.Mul(t, u).Mul(t, half).Sum(t, v).Mul(t, w)
+
+	if c&5 == 0 || c&5 == 5 {
+		t.Neg(t)
+	}
+
+	return t
+}
+
+// EllSwiftEncode returns the ElligatorSwift encoding of p,
+// a fresh random tweak is drawn for every call
+func EllSwiftEncode(p *Point) []byte {
+	if p.x == nil {
+		panic("the point at infinity cannot be encoded")
+	}
+
+	for {
+		u, err := rand.Int(rand.Reader, BitcoinOrder)
+		if err != nil {
+			panic("failed to generate u")
+		}
+
+		c, err := rand.Int(rand.Reader, big.NewInt(8))
+		if err != nil {
+			panic("failed to generate the case")
+		}
+
+		uField := S256Field(u)
+		if t := XSwiftECInv(uField, p.x, int(c.Int64())); t != nil {
+			encoded := make([]byte, EllSwiftSize)
+			u.FillBytes(encoded[:32])
+			t.num.FillBytes(encoded[32:])
+			return encoded
+		}
+	}
+}
+
+// EllSwiftDecode returns the x coordinate of an ElligatorSwift encoded point
+func EllSwiftDecode(encoded []byte) (*FieldElement, error) {
+	if len(encoded) != EllSwiftSize {
+		return nil, ErrInvalidEllSwift
+	}
+
+	u := big.NewInt(0).SetBytes(encoded[:32])
+	t := big.NewInt(0).SetBytes(encoded[32:])
+
+	return XSwiftEC(
+		S256Field(u.Mod(u, BitcoinOrder)),
+		S256Field(t.Mod(t, BitcoinOrder)),
+	), nil
+}
+
+// EllSwift returns the ElligatorSwift encoding of the public key
+func (p *PrivateKey) EllSwift() []byte {
+	return EllSwiftEncode(p.pubKey)
+}
+
+// EllSwiftECDHXOnly returns the x coordinate of the secret
+// multiplied by the ElligatorSwift encoded public key
+func (p *PrivateKey) EllSwiftECDHXOnly(theirs []byte) ([]byte, error) {
+	x, err := EllSwiftDecode(theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	point, ok := liftX(x)
+	if !ok {
+		return nil, fmt.Errorf("decoded x is not on the curve")
+	}
+
+	shared := make([]byte, 32)
+	point.ScalarMul(p.secret).x.num.FillBytes(shared)
+	return shared, nil
+}
+
+// V2ECDH computes the BIP324 shared secret between the two ElligatorSwift
+// encoded public keys, the initiator's encoding is always hashed first
+func (p *PrivateKey) V2ECDH(theirs, ours []byte, initiating bool) ([]byte, error) {
+	if len(ours) != EllSwiftSize {
+		return nil, ErrInvalidEllSwift
+	}
+
+	shared, err := p.EllSwiftECDHXOnly(theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	initiator, responder := theirs, ours
+	if initiating {
+		initiator, responder = ours, theirs
+	}
+
+	secret := hashes.TaggedHash(ellSwiftECDHTag, initiator, responder, shared)
+	return secret[:], nil
+}
+
+func isXOnCurve(x *FieldElement) bool {
+	_, ok := liftX(x)
+	return ok
+}
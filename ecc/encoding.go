@@ -0,0 +1,271 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Binary and text encodings, the text encoding is always the hex encoding
+// of the binary one and is what encoding/json uses:
+//
+//   - Point: compressed SEC, 0x00 for the point at infinity. Uncompressed
+//     SEC is accepted when decoding. Only secp256k1 points are supported
+//   - FieldElement: big endian number padded to the size of the order
+//   - Signature: strict DER, CompactSignature uses r || s instead
+//   - PrivateKey: refuses to be encoded, ExportablePrivateKey opts in
+//     and encodes the 32 bytes big endian secret
+
+const (
+	CompactSignatureSize = 64
+
+	compressedSecSize   = 33
+	uncompressedSecSize = 65
+	privateKeySize      = 32
+)
+
+var (
+	ErrInvalidEncoding  = errors.New("invalid encoding")
+	ErrPrivateKeyExport = errors.New("private keys are only encoded through ExportablePrivateKey")
+	ErrUnsupportedCurve = errors.New("only secp256k1 points can be encoded")
+
+	infinityEncoding = []byte{0x00}
+)
+
+func (p *Point) MarshalBinary() ([]byte, error) {
+	if !isS256Curve(p.a, p.b) {
+		return nil, ErrUnsupportedCurve
+	}
+
+	if p.x == nil {
+		return append([]byte{}, infinityEncoding...), nil
+	}
+
+	return p.compressedSec(), nil
+}
+
+func (p *Point) UnmarshalBinary(data []byte) error {
+	var (
+		decoded *Point
+		err     error
+	)
+
+	switch {
+	case bytes.Equal(data, infinityEncoding):
+		decoded = S256Point(nil, nil)
+	case len(data) == compressedSecSize && (data[0] == 0x02 || data[0] == 0x03),
+		len(data) == uncompressedSecSize && data[0] == 0x04:
+		decoded, err = FromSec(bytes.NewReader(data))
+	default:
+		err = fmt.Errorf("unexpected %d bytes sec encoding", len(data))
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: point: %w", ErrInvalidEncoding, err)
+	}
+
+	*p = *decoded
+	return nil
+}
+
+func (p *Point) MarshalText() ([]byte, error) {
+	return marshalHex(p.MarshalBinary())
+}
+
+func (p *Point) UnmarshalText(text []byte) error {
+	data, err := unmarshalHex(text)
+	if err != nil {
+		return err
+	}
+
+	return p.UnmarshalBinary(data)
+}
+
+func (f *FieldElement) MarshalBinary() ([]byte, error) {
+	if f.order == nil {
+		return nil, fmt.Errorf("%w: field element without an order", ErrInvalidEncoding)
+	}
+
+	num := big.NewInt(0).Mod(f.int(), f.order)
+	return num.FillBytes(make([]byte, fieldElementSize(f.order))), nil
+}
+
+// UnmarshalBinary decodes a number lower than the order of f, which
+// defaults to the secp256k1 field when f was not initialised
+func (f *FieldElement) UnmarshalBinary(data []byte) error {
+	order := f.order
+	if order == nil {
+		order = BitcoinOrder
+	}
+
+	if len(data) != fieldElementSize(order) {
+		return fmt.Errorf("%w: field element must be %d bytes long", ErrInvalidEncoding, fieldElementSize(order))
+	}
+
+	num := big.NewInt(0).SetBytes(data)
+	if num.Cmp(order) >= 0 {
+		return fmt.Errorf("%w: field element is not lower than the order", ErrInvalidEncoding)
+	}
+
+	f.order, f.num = order, num
+	return nil
+}
+
+func (f *FieldElement) MarshalText() ([]byte, error) {
+	return marshalHex(f.MarshalBinary())
+}
+
+func (f *FieldElement) UnmarshalText(text []byte) error {
+	data, err := unmarshalHex(text)
+	if err != nil {
+		return err
+	}
+
+	return f.UnmarshalBinary(data)
+}
+
+func (s *Signature) MarshalBinary() ([]byte, error) {
+	return s.Der(), nil
+}
+
+func (s *Signature) UnmarshalBinary(data []byte) error {
+	sig, err := ParseDER(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+
+	*s = *sig
+	return nil
+}
+
+func (s *Signature) MarshalText() ([]byte, error) {
+	return marshalHex(s.MarshalBinary())
+}
+
+func (s *Signature) UnmarshalText(text []byte) error {
+	data, err := unmarshalHex(text)
+	if err != nil {
+		return err
+	}
+
+	return s.UnmarshalBinary(data)
+}
+
+// CompactSignature encodes a signature as the 32 bytes r followed by
+// the 32 bytes s, convert with (*CompactSignature)(sig)
+type CompactSignature Signature
+
+func (s *CompactSignature) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, CompactSignatureSize)
+	s.r.int().FillBytes(buf[:32])
+	s.s.int().FillBytes(buf[32:])
+	return buf, nil
+}
+
+func (s *CompactSignature) UnmarshalBinary(data []byte) error {
+	if len(data) != CompactSignatureSize {
+		return fmt.Errorf("%w: compact signature must be %d bytes long", ErrInvalidEncoding, CompactSignatureSize)
+	}
+
+	r, sv := big.NewInt(0).SetBytes(data[:32]), big.NewInt(0).SetBytes(data[32:])
+	for _, v := range []*big.Int{r, sv} {
+		if v.Sign() == 0 || v.Cmp(BitcoinN) >= 0 {
+			return fmt.Errorf("%w: signature values must be in the range [1, n)", ErrInvalidEncoding)
+		}
+	}
+
+	s.r, s.s = NewFieldElement(BitcoinN, r), NewFieldElement(BitcoinN, sv)
+	return nil
+}
+
+func (s *CompactSignature) MarshalText() ([]byte, error) {
+	return marshalHex(s.MarshalBinary())
+}
+
+func (s *CompactSignature) UnmarshalText(text []byte) error {
+	data, err := unmarshalHex(text)
+	if err != nil {
+		return err
+	}
+
+	return s.UnmarshalBinary(data)
+}
+
+// MarshalBinary always fails so private keys are not encoded by accident
+func (p *PrivateKey) MarshalBinary() ([]byte, error) {
+	return nil, ErrPrivateKeyExport
+}
+
+// MarshalText always fails so private keys are not encoded by accident
+func (p *PrivateKey) MarshalText() ([]byte, error) {
+	return nil, ErrPrivateKeyExport
+}
+
+// ExportablePrivateKey is the explicit opt-in to encode a private key
+type ExportablePrivateKey struct {
+	*PrivateKey
+}
+
+func (p ExportablePrivateKey) MarshalBinary() ([]byte, error) {
+	if p.PrivateKey == nil {
+		return nil, fmt.Errorf("%w: nil private key", ErrInvalidEncoding)
+	}
+
+	return p.secret.FillBytes(make([]byte, privateKeySize)), nil
+}
+
+func (p *ExportablePrivateKey) UnmarshalBinary(data []byte) error {
+	if len(data) != privateKeySize {
+		return fmt.Errorf("%w: private key must be %d bytes long", ErrInvalidEncoding, privateKeySize)
+	}
+
+	secret := big.NewInt(0).SetBytes(data)
+	if secret.Sign() == 0 || secret.Cmp(BitcoinN) >= 0 {
+		return fmt.Errorf("%w: private key must be in the range [1, n)", ErrInvalidEncoding)
+	}
+
+	p.PrivateKey = NewPrivateKey(secret)
+	return nil
+}
+
+func (p ExportablePrivateKey) MarshalText() ([]byte, error) {
+	return marshalHex(p.MarshalBinary())
+}
+
+func (p *ExportablePrivateKey) UnmarshalText(text []byte) error {
+	data, err := unmarshalHex(text)
+	if err != nil {
+		return err
+	}
+
+	return p.UnmarshalBinary(data)
+}
+
+func isS256Curve(a, b *FieldElement) bool {
+	return a.order.Cmp(BitcoinOrder) == 0 && a.int().Sign() == 0 && b.int().Cmp(big.NewInt(7)) == 0
+}
+
+func fieldElementSize(order *big.Int) int {
+	return (order.BitLen() + 7) / 8
+}
+
+func marshalHex(data []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	text := make([]byte, hex.EncodedLen(len(data)))
+	hex.Encode(text, data)
+	return text, nil
+}
+
+func unmarshalHex(text []byte) ([]byte, error) {
+	data := make([]byte, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(data, text); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+
+	return data, nil
+}
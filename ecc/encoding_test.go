@@ -0,0 +1,168 @@
+package ecc_test
+
+import (
+	"ecc"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointEncoding(t *testing.T) {
+	p := ecc.NewPrivateKey(big.NewInt(5001)).PublicKey()
+
+	text, err := p.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, p.Sec(true), string(text))
+
+	decoded := new(ecc.Point)
+	require.NoError(t, decoded.UnmarshalText(text))
+	require.True(t, p.EqualTo(decoded))
+
+	// uncompressed points are accepted but always encoded compressed
+	require.NoError(t, decoded.UnmarshalText([]byte(p.Sec(false))))
+	require.True(t, p.EqualTo(decoded))
+
+	infinity, err := ecc.S256Point(nil, nil).MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00}, infinity)
+	require.NoError(t, decoded.UnmarshalBinary(infinity))
+	require.Equal(t, ecc.S256Point(nil, nil), decoded)
+
+	invalid := map[string]string{
+		"empty":             "",
+		"not hex":           "zz",
+		"odd length":        p.Sec(true)[1:],
+		"truncated":         p.Sec(true)[:64],
+		"trailing bytes":    p.Sec(true) + "00",
+		"uncompressed size": "04" + p.Sec(true)[2:],
+		"hybrid prefix":     "06" + p.Sec(false)[2:],
+		"x not on curve":    "02" + strings.Repeat("0", 63) + "5",
+		"x over p":          "02" + strings.Repeat("f", 64),
+	}
+
+	for name, h := range invalid {
+		require.ErrorIs(t, decoded.UnmarshalText([]byte(h)), ecc.ErrInvalidEncoding, name)
+	}
+
+	// points of other curves are not encoded
+	order := big.NewInt(223)
+	a, b := ecc.NewFieldElement(order, big.NewInt(0)), ecc.NewFieldElement(order, big.NewInt(7))
+	toy := ecc.NewPoint(ecc.NewFieldElement(order, big.NewInt(192)), ecc.NewFieldElement(order, big.NewInt(105)), a, b)
+	_, err = toy.MarshalText()
+	require.ErrorIs(t, err, ecc.ErrUnsupportedCurve)
+}
+
+func TestFieldElementEncoding(t *testing.T) {
+	f := ecc.S256Field(big.NewInt(0x1234))
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, 32)
+
+	decoded := new(ecc.FieldElement)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.True(t, f.EqualTo(decoded))
+
+	// the order of an initialised element drives the size
+	order := big.NewInt(223)
+	small := ecc.NewFieldElement(order, big.NewInt(-1))
+	text, err := small.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "de", string(text))
+
+	decoded = ecc.NewFieldElement(order, big.NewInt(0))
+	require.NoError(t, decoded.UnmarshalText(text))
+	require.True(t, decoded.EqualTo(ecc.NewFieldElement(order, big.NewInt(222))))
+	require.ErrorIs(t, decoded.UnmarshalText([]byte("df")), ecc.ErrInvalidEncoding)
+	require.ErrorIs(t, decoded.UnmarshalText([]byte("00de")), ecc.ErrInvalidEncoding)
+
+	require.ErrorIs(t, new(ecc.FieldElement).UnmarshalBinary(ecc.BitcoinOrder.Bytes()), ecc.ErrInvalidEncoding)
+}
+
+func TestSignatureEncoding(t *testing.T) {
+	derHex := "3045022037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c60221008ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec"
+	compactHex := "37206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c68ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec"
+
+	sig := new(ecc.Signature)
+	require.NoError(t, sig.UnmarshalText([]byte(derHex)))
+
+	text, err := sig.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, derHex, string(text))
+
+	text, err = (*ecc.CompactSignature)(sig).MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, compactHex, string(text))
+
+	compact := new(ecc.CompactSignature)
+	require.NoError(t, compact.UnmarshalText([]byte(compactHex)))
+	der, err := (*ecc.Signature)(compact).MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, derHex, string(der))
+
+	require.ErrorIs(t, sig.UnmarshalText([]byte(compactHex)), ecc.ErrInvalidEncoding)
+	require.ErrorIs(t, sig.UnmarshalText([]byte(derHex+"00")), ecc.ErrInvalidDER)
+	require.ErrorIs(t, compact.UnmarshalText([]byte(derHex)), ecc.ErrInvalidEncoding)
+	require.ErrorIs(t, compact.UnmarshalText([]byte(strings.Repeat("0", 128))), ecc.ErrInvalidEncoding)
+	require.ErrorIs(t, compact.UnmarshalText([]byte(compactHex[:64]+hex.EncodeToString(ecc.BitcoinN.Bytes()))), ecc.ErrInvalidEncoding)
+}
+
+func TestPrivateKeyEncoding(t *testing.T) {
+	key := ecc.NewPrivateKey(big.NewInt(0xc0ffee))
+
+	_, err := json.Marshal(struct{ Key *ecc.PrivateKey }{key})
+	require.ErrorIs(t, err, ecc.ErrPrivateKeyExport)
+	_, err = key.MarshalBinary()
+	require.ErrorIs(t, err, ecc.ErrPrivateKeyExport)
+
+	encoded, err := json.Marshal(struct{ Key ecc.ExportablePrivateKey }{ecc.ExportablePrivateKey{key}})
+	require.NoError(t, err)
+	require.Equal(t, `{"Key":"`+strings.Repeat("0", 58)+`c0ffee"}`, string(encoded))
+
+	var decoded struct{ Key ecc.ExportablePrivateKey }
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, key.Secret(), decoded.Key.Secret())
+	require.True(t, key.PublicKey().EqualTo(decoded.Key.PublicKey()))
+
+	exported := new(ecc.ExportablePrivateKey)
+	for _, h := range []string{
+		strings.Repeat("0", 64),
+		hex.EncodeToString(ecc.BitcoinN.Bytes()),
+		strings.Repeat("0", 62) + "01" + "00",
+		"01",
+	} {
+		require.ErrorIs(t, exported.UnmarshalText([]byte(h)), ecc.ErrInvalidEncoding, h)
+	}
+}
+
+func TestJSONDocument(t *testing.T) {
+	key := ecc.NewPrivateKey(big.NewInt(77))
+	z := big.NewInt(0xabcdef)
+
+	type document struct {
+		PubKey    *ecc.Point            `json:"pubkey"`
+		Signature *ecc.Signature        `json:"signature"`
+		Z         *ecc.FieldElement     `json:"z"`
+		Compact   *ecc.CompactSignature `json:"compact"`
+	}
+
+	sig := key.Sign(z)
+	in := document{
+		PubKey:    key.PublicKey(),
+		Signature: sig,
+		Z:         ecc.NewFieldElement(ecc.BitcoinN, z),
+		Compact:   (*ecc.CompactSignature)(sig),
+	}
+
+	encoded, err := json.Marshal(in)
+	require.NoError(t, err)
+
+	var out document
+	out.Z = ecc.NewFieldElement(ecc.BitcoinN, big.NewInt(0))
+	require.NoError(t, json.Unmarshal(encoded, &out))
+	require.True(t, out.PubKey.Verify(out.Z, out.Signature))
+	require.True(t, out.PubKey.Verify(out.Z, (*ecc.Signature)(out.Compact)))
+}
@@ -35,38 +35,30 @@ func (f *FieldElement) checkOrder(other *FieldElement) {
 	}
 }
 
+// Add returns a new element f + other. It predates the in-place methods
+// and keeps its immutable form, see Sum for the in-place addition
 func (f *FieldElement) Add(other *FieldElement) *FieldElement {
-	f.checkOrder(other)
-
-	return NewFieldElement(f.order,
-		big.NewInt(0).Mod(big.NewInt(0).Add(f.num, other.num), f.order))
+	return new(FieldElement).Sum(f, other)
 }
 
 func (f *FieldElement) Negate() *FieldElement {
-	return NewFieldElement(f.order, big.NewInt(0).Sub(f.order, f.num))
+	return new(FieldElement).Neg(f)
 }
 
 func (f *FieldElement) Substract(other *FieldElement) *FieldElement {
-	f.checkOrder(other)
-	return f.Add(other.Negate())
+	return new(FieldElement).Sub(f, other)
 }
 
 func (f *FieldElement) Multiply(other *FieldElement) *FieldElement {
-	f.checkOrder(other)
-
-	return NewFieldElement(f.order,
-		big.NewInt(0).Mod(big.NewInt(0).Mul(f.num, other.num), f.order))
+	return new(FieldElement).Mul(f, other)
 }
 
 func (f *FieldElement) Power(pwr *big.Int) *FieldElement {
-	t := big.NewInt(0).Mod(pwr, big.NewInt(0).Sub(f.order, big.NewInt(1)))
-	res := big.NewInt(0).Exp(f.num, t, f.order)
-	modRes := big.NewInt(0).Mod(res, f.order)
-	return NewFieldElement(f.order, modRes)
+	return new(FieldElement).Exp(f, pwr)
 }
 
 func (f *FieldElement) ScalarMul(v *big.Int) *FieldElement {
-	return NewFieldElement(f.order, big.NewInt(0).Mod(big.NewInt(0).Mul(f.num, v), f.order))
+	return new(FieldElement).MulInt(f, v)
 }
 
 func (f *FieldElement) Sqrt() *FieldElement {
@@ -81,21 +73,170 @@ func (f *FieldElement) Sqrt() *FieldElement {
 	return f.Power(div)
 }
 
+// sqrt returns the square root of f when it exists
+func sqrt(f *FieldElement) (*FieldElement, bool) {
+	root := f.Sqrt()
+	return root, new(FieldElement).Square(root).EqualTo(f)
+}
+
 func (f *FieldElement) Divide(other *FieldElement) *FieldElement {
-	f.checkOrder(other)
+	return new(FieldElement).Div(f, other)
+}
+
+func (f *FieldElement) Inverse() *FieldElement {
+	return new(FieldElement).Inv(f)
+}
+
+// The methods below follow the math/big convention: the receiver holds
+// the result, its storage is reused across calls and it is returned to
+// allow chaining, e.g. z.Mul(x, y).Sum(z, w). The receiver may alias any
+// of the operands. The zero value is ready to be used as a receiver.
+
+// Set sets z to x and returns z
+func (z *FieldElement) Set(x *FieldElement) *FieldElement {
+	z.order = x.order
+	z.int().Set(x.num)
+	return z
+}
+
+// Sum sets z to x + y and returns z. It is the in-place counterpart of
+// Add, which allocates: Add was already the immutable method so unlike
+// Sub, Neg or Mul the in-place addition could not take the math/big name
+func (z *FieldElement) Sum(x, y *FieldElement) *FieldElement {
+	x.checkOrder(y)
+	z.order = x.order
+	z.int().Add(x.num, y.num)
+	return z.reduce()
+}
+
+// Sub sets z to x - y and returns z
+func (z *FieldElement) Sub(x, y *FieldElement) *FieldElement {
+	x.checkOrder(y)
+	z.order = x.order
+	z.int().Sub(x.num, y.num)
+	return z.reduce()
+}
+
+// Neg sets z to -x and returns z
+func (z *FieldElement) Neg(x *FieldElement) *FieldElement {
+	z.order = x.order
+	z.int().Sub(x.order, x.num)
+	return z.reduce()
+}
+
+// Mul sets z to x * y and returns z
+func (z *FieldElement) Mul(x, y *FieldElement) *FieldElement {
+	x.checkOrder(y)
+	z.order = x.order
+	z.int().Mul(x.num, y.num)
+	z.num.Mod(z.num, z.order)
+	return z
+}
+
+// Square sets z to x ^ 2 and returns z
+func (z *FieldElement) Square(x *FieldElement) *FieldElement {
+	return z.Mul(x, x)
+}
+
+// MulInt sets z to x * v and returns z
+func (z *FieldElement) MulInt(x *FieldElement, v *big.Int) *FieldElement {
+	z.order = x.order
+	z.int().Mul(x.num, v)
+	z.num.Mod(z.num, z.order)
+	return z
+}
+
+// Exp sets z to x ^ pwr and returns z, negative
+// exponents are reduced by Fermat's little theorem
+func (z *FieldElement) Exp(x *FieldElement, pwr *big.Int) *FieldElement {
+	t := big.NewInt(0).Mod(pwr, big.NewInt(0).Sub(x.order, big.NewInt(1)))
+	z.order = x.order
+	z.int().Exp(x.num, t, z.order)
+	return z
+}
+
+// Inv sets z to x ^ (p - 2), the multiplicative inverse of x, and returns z
+func (z *FieldElement) Inv(x *FieldElement) *FieldElement {
+	return z.Exp(x, big.NewInt(0).Sub(x.order, big.NewInt(2)))
+}
+
+// Div sets z to x / y and returns z
+func (z *FieldElement) Div(x, y *FieldElement) *FieldElement {
+	x.checkOrder(y)
 
 	// c * b = a
 	// a / b = c
 
 	// a / b == a * b ^ (p - 2)
-	reverseOfOther := other.Power(big.NewInt(0).Sub(f.order, big.NewInt(2)))
-	return f.Multiply(reverseOfOther)
+	if z == x {
+		return z.Mul(x, new(FieldElement).Inv(y))
+	}
+
+	return z.Inv(y).Mul(x, z)
 }
 
-func (f *FieldElement) Inverse() *FieldElement {
-	return f.Power(big.NewInt(0).Sub(f.order, big.NewInt(2)))
+func (z *FieldElement) int() *big.Int {
+	if z.num == nil {
+		z.num = new(big.Int)
+	}
+
+	return z.num
+}
+
+// reduce brings z.num back to [0, order), a single correction
+// is enough after additions so the division is rarely needed
+func (z *FieldElement) reduce() *FieldElement {
+	if z.num.Sign() < 0 {
+		z.num.Add(z.num, z.order)
+	} else if z.num.Cmp(z.order) >= 0 {
+		z.num.Sub(z.num, z.order)
+	}
+
+	if z.num.Sign() < 0 || z.num.Cmp(z.order) >= 0 {
+		z.num.Mod(z.num, z.order)
+	}
+
+	return z
 }
 
 func S256Field(num *big.Int) *FieldElement {
 	return NewFieldElement(BitcoinOrder, num)
 }
+
+// BatchInverse inverts every element with Montgomery's trick: a single
+// field inversion plus three multiplications per element. Zero elements
+// are mapped to zero, the same result Inverse gives for them.
+func BatchInverse(elements []*FieldElement) []*FieldElement {
+	if len(elements) == 0 {
+		return nil
+	}
+
+	order := elements[0].order
+
+	// prefix[i] holds the product of every non-zero element before i
+	prefix := make([]*big.Int, len(elements))
+	acc := big.NewInt(1)
+	for i, e := range elements {
+		elements[0].checkOrder(e)
+		prefix[i] = big.NewInt(0).Set(acc)
+		if e.num.Sign() != 0 {
+			acc.Mod(acc.Mul(acc, e.num), order)
+		}
+	}
+
+	inv := NewFieldElement(order, acc).Inverse().num
+	inverses := make([]*FieldElement, len(elements))
+	for i := len(elements) - 1; i >= 0; i-- {
+		e := elements[i]
+		if e.num.Sign() == 0 {
+			inverses[i] = NewFieldElement(order, big.NewInt(0))
+			continue
+		}
+
+		inverses[i] = NewFieldElement(order,
+			big.NewInt(0).Mod(big.NewInt(0).Mul(inv, prefix[i]), order))
+		inv = big.NewInt(0).Mod(big.NewInt(0).Mul(inv, e.num), order)
+	}
+
+	return inverses
+}
@@ -92,3 +92,53 @@ func TestSqrt(t *testing.T) {
 
 	fmt.Println("point from the SEC compressed format is on the curve")
 }
+
+func TestBatchInverse(t *testing.T) {
+	order := big.NewInt(223)
+
+	var elements []*ecc.FieldElement
+	for num := 0; num < 223; num++ {
+		elements = append(elements, ecc.NewFieldElement(order, big.NewInt(int64(num))))
+	}
+
+	inverses := ecc.BatchInverse(elements)
+	require.Len(t, inverses, len(elements))
+
+	for idx, e := range elements {
+		require.True(t, e.Inverse().EqualTo(inverses[idx]), "element #%d", idx)
+	}
+
+	require.Nil(t, ecc.BatchInverse(nil))
+}
+
+func TestFieldElementInPlace(t *testing.T) {
+	order := big.NewInt(223)
+
+	f44 := ecc.NewFieldElement(order, big.NewInt(44))
+	f33 := ecc.NewFieldElement(order, big.NewInt(33))
+
+	z := new(ecc.FieldElement)
+	require.True(t, z.Sum(f44, f33).EqualTo(f44.Add(f33)))
+	require.True(t, z.Sub(f33, f44).EqualTo(f33.Substract(f44)))
+	require.True(t, z.Neg(f44).EqualTo(f44.Negate()))
+	require.True(t, z.Mul(f44, f33).EqualTo(f44.Multiply(f33)))
+	require.True(t, z.Square(f44).EqualTo(f44.Power(big.NewInt(2))))
+	require.True(t, z.MulInt(f44, big.NewInt(-3)).EqualTo(f44.ScalarMul(big.NewInt(-3))))
+	require.True(t, z.Exp(f44, big.NewInt(-5)).EqualTo(f44.Power(big.NewInt(-5))))
+	require.True(t, z.Inv(f33).EqualTo(f33.Inverse()))
+	require.True(t, z.Div(f44, f33).EqualTo(f44.Divide(f33)))
+
+	// the receiver may alias the operands and is reused across calls
+	z.Set(f44)
+	num := z.Sum(z, z).Mul(z, f33).Div(z, z)
+	require.Same(t, z, num)
+	require.True(t, z.EqualTo(ecc.NewFieldElement(order, big.NewInt(1))))
+
+	// the operands are left untouched
+	require.True(t, f44.EqualTo(ecc.NewFieldElement(order, big.NewInt(44))))
+	require.True(t, f33.EqualTo(ecc.NewFieldElement(order, big.NewInt(33))))
+
+	// negating zero stays inside the field
+	zero := ecc.NewFieldElement(order, big.NewInt(0))
+	require.True(t, zero.Negate().EqualTo(zero))
+}
@@ -2,10 +2,24 @@ module ecc
 
 go 1.23.1
 
-require github.com/stretchr/testify v1.10.0
+replace base58 => ../base58
+
+replace chaincfg => ../chaincfg
+
+replace hashes => ../hashes
+
+replace wire => ../wire
+
+require (
+	base58 v0.0.0-00010101000000-000000000000
+	chaincfg v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+	hashes v0.0.0-00010101000000-000000000000
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
+	wire v0.0.0-00010101000000-000000000000 // indirect
 )
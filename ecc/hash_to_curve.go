@@ -0,0 +1,224 @@
+package ecc
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// Implementation of the secp256k1_XMD:SHA-256_SSWU_RO_ and
+// secp256k1_XMD:SHA-256_SSWU_NU_ suites from RFC 9380. Since secp256k1
+// has a = 0 the simplified SWU map runs on the 3-isogenous curve
+// y ^ 2 = x ^ 3 + A' * x + B' and the result is mapped back with the isogeny
+
+var (
+	ErrEmptyDST = errors.New("domain separation tag cannot be empty")
+
+	// the length in bytes of each field element drawn from the
+	// expanded message: ceil((ceil(log2(p)) + k) / 8) with k = 128
+	hashToFieldL = 48
+
+	oversizeDSTPrefix = []byte("H2C-OVERSIZE-DST-")
+
+	isoA = hexToS256Field("3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533")
+	isoB = S256Field(big.NewInt(1771))
+	isoZ = S256Field(big.NewInt(0).Sub(BitcoinOrder, big.NewInt(11)))
+
+	isoXNum = []*FieldElement{
+		hexToS256Field("8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7"),
+		hexToS256Field("07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581"),
+		hexToS256Field("534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262"),
+		hexToS256Field("8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88c"),
+	}
+
+	isoXDen = []*FieldElement{
+		hexToS256Field("d35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49b"),
+		hexToS256Field("edadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14"),
+		S256Field(big.NewInt(1)),
+	}
+
+	isoYNum = []*FieldElement{
+		hexToS256Field("4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23c"),
+		hexToS256Field("c75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3"),
+		hexToS256Field("29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931"),
+		hexToS256Field("2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84"),
+	}
+
+	isoYDen = []*FieldElement{
+		hexToS256Field("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93b"),
+		hexToS256Field("7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573"),
+		hexToS256Field("6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76f"),
+		S256Field(big.NewInt(1)),
+	}
+)
+
+func hexToS256Field(h string) *FieldElement {
+	n, ok := big.NewInt(0).SetString(h, 16)
+	if !ok {
+		panic("invalid hex constant " + h)
+	}
+
+	return S256Field(n)
+}
+
+// HashToCurve hashes msg to a point of secp256k1 with no known discrete
+// log, the output is indistinguishable from a random oracle
+func HashToCurve(msg, dst []byte) (*Point, error) {
+	u, err := hashToField(msg, dst, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToCurve(u[0]).Add(mapToCurve(u[1])), nil
+}
+
+// EncodeToCurve is the nonuniform variant of HashToCurve, it is cheaper
+// but the output only covers about half of the points of the curve
+func EncodeToCurve(msg, dst []byte) (*Point, error) {
+	u, err := hashToField(msg, dst, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToCurve(u[0]), nil
+}
+
+func hashToField(msg, dst []byte, count int) ([]*FieldElement, error) {
+	uniform, err := expandMessageXMD(msg, dst, count*hashToFieldL)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]*FieldElement, count)
+	for i := range elements {
+		tv := uniform[i*hashToFieldL : (i+1)*hashToFieldL]
+		elements[i] = S256Field(big.NewInt(0).Mod(big.NewInt(0).SetBytes(tv), BitcoinOrder))
+	}
+
+	return elements, nil
+}
+
+func expandMessageXMD(msg, dst []byte, lenInBytes int) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+
+	if len(dst) > 255 {
+		h := sha256.New()
+		h.Write(oversizeDSTPrefix)
+		h.Write(dst)
+		dst = h.Sum(nil)
+	}
+
+	ell := (lenInBytes + sha256.Size - 1) / sha256.Size
+	if ell > 255 || lenInBytes > 65535 {
+		return nil, errors.New("requested too many bytes from expand_message_xmd")
+	}
+
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	uniform := make([]byte, 0, ell*sha256.Size)
+	uniform = append(uniform, bi...)
+	for i := 2; i <= ell; i++ {
+		xored := make([]byte, sha256.Size)
+		for j := range xored {
+			xored[j] = b0[j] ^ bi[j]
+		}
+
+		h.Reset()
+		h.Write(xored)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		uniform = append(uniform, bi...)
+	}
+
+	return uniform[:lenInBytes], nil
+}
+
+// mapToCurve applies the simplified SWU map to the isogenous
+// curve followed by the 3-isogeny back to secp256k1
+func mapToCurve(u *FieldElement) *Point {
+	x, y := simplifiedSWU(u)
+	return isoMap(x, y)
+}
+
+func simplifiedSWU(u *FieldElement) (x, y *FieldElement) {
+	// tv1 = 1 / (Z ^ 2 * u ^ 4 + Z * u ^ 2)
+	zu2 := new(FieldElement).Square(u)
+	zu2.Mul(zu2, isoZ)
+	tv1 := new(FieldElement).Square(zu2)
+	tv1.Sum(tv1, zu2)
+
+	x1 := new(FieldElement)
+	if tv1.num.Sign() == 0 {
+		// x1 = B / (Z * A)
+		x1.Mul(isoZ, isoA).Div(isoB, x1)
+	} else {
+		// x1 = (-B / A) * (1 + 1 / tv1)
+		tv1.Inv(tv1).Sum(tv1, S256Field(big.NewInt(1)))
+		x1.Neg(isoB).Div(x1, isoA).Mul(x1, tv1)
+	}
+
+	gx1 := isoCurveRHS(x1)
+	if y1 := gx1.Sqrt(); new(FieldElement).Square(y1).EqualTo(gx1) {
+		x, y = x1, y1
+	} else {
+		// x2 = Z * u ^ 2 * x1
+		x = new(FieldElement).Mul(zu2, x1)
+		y = isoCurveRHS(x).Sqrt()
+	}
+
+	if u.num.Bit(0) != y.num.Bit(0) {
+		y.Neg(y)
+	}
+
+	return x, y
+}
+
+// isoCurveRHS computes x ^ 3 + A' * x + B'
+func isoCurveRHS(x *FieldElement) *FieldElement {
+	ax := new(FieldElement).Mul(isoA, x)
+	rhs := new(FieldElement).Square(x)
+	return rhs.Mul(rhs, x).Sum(rhs, ax).Sum(rhs, isoB)
+}
+
+func isoMap(x, y *FieldElement) *Point {
+	xNum := evalPolynomial(isoXNum, x)
+	xDen := evalPolynomial(isoXDen, x)
+	yNum := evalPolynomial(isoYNum, x)
+	yDen := evalPolynomial(isoYDen, x)
+
+	if xDen.num.Sign() == 0 || yDen.num.Sign() == 0 {
+		return S256Point(nil, nil)
+	}
+
+	xNum.Div(xNum, xDen)
+	yNum.Div(yNum, yDen).Mul(yNum, y)
+
+	return S256Point(xNum.num, yNum.num)
+}
+
+// evalPolynomial uses horner's method, coefficients
+// are ordered from the lowest degree to the highest
+func evalPolynomial(coefficients []*FieldElement, x *FieldElement) *FieldElement {
+	result := new(FieldElement).Set(coefficients[len(coefficients)-1])
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		result.Mul(result, x).Sum(result, coefficients[i])
+	}
+
+	return result
+}
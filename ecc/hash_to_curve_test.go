@@ -0,0 +1,76 @@
+package ecc_test
+
+import (
+	"ecc"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type hashToCurveVector struct {
+	msg  string
+	x, y string
+}
+
+// test vectors from RFC 9380, appendix J.8
+var (
+	hashToCurveMessages = []string{
+		"",
+		"abc",
+		"abcdef0123456789",
+		"q128_" + strings.Repeat("q", 128),
+		"a512_" + strings.Repeat("a", 512),
+	}
+
+	hashToCurveVectors = []hashToCurveVector{
+		{hashToCurveMessages[0], "c1cae290e291aee617ebaef1be6d73861479c48b841eaba9b7b5852ddfeb1346", "64fa678e07ae116126f08b022a94af6de15985c996c3a91b64c406a960e51067"},
+		{hashToCurveMessages[1], "3377e01eab42db296b512293120c6cee72b6ecf9f9205760bd9ff11fb3cb2c4b", "7f95890f33efebd1044d382a01b1bee0900fb6116f94688d487c6c7b9c8371f6"},
+		{hashToCurveMessages[2], "bac54083f293f1fe08e4a70137260aa90783a5cb84d3f35848b324d0674b0e3a", "4436476085d4c3c4508b60fcf4389c40176adce756b398bdee27bca19758d828"},
+		{hashToCurveMessages[3], "e2167bc785333a37aa562f021f1e881defb853839babf52a7f72b102e41890e9", "f2401dd95cc35867ffed4f367cd564763719fbc6a53e969fb8496a1e6685d873"},
+		{hashToCurveMessages[4], "e3c8d35aaaf0b9b647e88a0a0a7ee5d5bed5ad38238152e4e6fd8c1f8cb7c998", "8446eeb6181bf12f56a9d24e262221cc2f0c4725c7e3803024b5888ee5823aa6"},
+	}
+
+	encodeToCurveVectors = []hashToCurveVector{
+		{hashToCurveMessages[0], "a4792346075feae77ac3b30026f99c1441b4ecf666ded19b7522cf65c4c55c5b", "62c59e2a6aeed1b23be5883e833912b08ba06be7f57c0e9cdc663f31639ff3a7"},
+		{hashToCurveMessages[1], "3f3b5842033fff837d504bb4ce2a372bfeadbdbd84a1d2b678b6e1d7ee426b9d", "902910d1fef15d8ae2006fc84f2a5a7bda0e0407dc913062c3a493c4f5d876a5"},
+		{hashToCurveMessages[2], "07644fa6281c694709f53bdd21bed94dab995671e4a8cd1904ec4aa50c59bfdf", "c79f8d1dad79b6540426922f7fbc9579c3018dafeffcd4552b1626b506c21e7b"},
+		{hashToCurveMessages[3], "b734f05e9b9709ab631d960fa26d669c4aeaea64ae62004b9d34f483aa9acc33", "03fc8a4a5a78632e2eb4d8460d69ff33c1d72574b79a35e402e801f2d0b1d6ee"},
+		{hashToCurveMessages[4], "17d22b867658977b5002dbe8d0ee70a8cfddec3eec50fb93f36136070fd9fa6c", "e9178ff02f4dab73480f8dd590328aea99856a7b6cc8e5a6cdf289ecc2a51718"},
+	}
+)
+
+func (v hashToCurveVector) point() *ecc.Point {
+	x, _ := new(big.Int).SetString(v.x, 16)
+	y, _ := new(big.Int).SetString(v.y, 16)
+	return ecc.S256Point(x, y)
+}
+
+func TestHashToCurve(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_RO_")
+
+	for _, v := range hashToCurveVectors {
+		p, err := ecc.HashToCurve([]byte(v.msg), dst)
+		require.NoError(t, err)
+		require.True(t, v.point().EqualTo(p), "msg %q", v.msg)
+	}
+
+	_, err := ecc.HashToCurve([]byte("abc"), nil)
+	require.ErrorIs(t, err, ecc.ErrEmptyDST)
+}
+
+func TestEncodeToCurve(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_NU_")
+
+	for _, v := range encodeToCurveVectors {
+		p, err := ecc.EncodeToCurve([]byte(v.msg), dst)
+		require.NoError(t, err)
+		require.True(t, v.point().EqualTo(p), "msg %q", v.msg)
+	}
+
+	// an oversized tag is hashed down instead of being rejected
+	p, err := ecc.EncodeToCurve([]byte("abc"), []byte(strings.Repeat("d", 300)))
+	require.NoError(t, err)
+	require.NotNil(t, p)
+}
@@ -0,0 +1,211 @@
+package ecc
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// JacobianPoint represents the affine point (x / z^2, y / z^3), letting
+// additions and doublings run without a field inversion. The point at
+// infinity is any point with z == 0
+type JacobianPoint struct {
+	// coefficients of the curve
+	a *FieldElement
+	b *FieldElement
+
+	x *FieldElement
+	y *FieldElement
+	z *FieldElement
+}
+
+func NewJacobianPoint(p *Point) *JacobianPoint {
+	if p.x == nil {
+		return newJacobianInfinity(p.a, p.b)
+	}
+
+	return &JacobianPoint{
+		a: p.a,
+		b: p.b,
+		x: p.x,
+		y: p.y,
+		z: NewFieldElement(p.x.order, big.NewInt(1)),
+	}
+}
+
+func newJacobianInfinity(a, b *FieldElement) *JacobianPoint {
+	one := NewFieldElement(a.order, big.NewInt(1))
+	return &JacobianPoint{
+		a: a,
+		b: b,
+		x: one,
+		y: one,
+		z: NewFieldElement(a.order, big.NewInt(0)),
+	}
+}
+
+func (p *JacobianPoint) IsInfinity() bool {
+	return p.z.num.Sign() == 0
+}
+
+func (p *JacobianPoint) Double() *JacobianPoint {
+	if p.IsInfinity() || p.y.num.Sign() == 0 {
+		return newJacobianInfinity(p.a, p.b)
+	}
+
+	var (
+		yPwr2 = new(FieldElement).Square(p.y)
+		s     = new(FieldElement)
+		m     = new(FieldElement)
+		t     = new(FieldElement)
+	)
+
+	// S = 4 * x * y ^ 2
+	s.Mul(p.x, yPwr2).MulInt(s, big.NewInt(4))
+
+	// M = 3 * x ^ 2 + a * z ^ 4
+	m.Square(p.x).MulInt(m, big.NewInt(3))
+	if p.a.num.Sign() != 0 {
+		t.Square(p.z).Square(t).Mul(t, p.a)
+		m.Sum(m, t)
+	}
+
+	// x3 = M ^ 2 - 2 * S
+	x3 := new(FieldElement).Square(m)
+	x3.Sub(x3, t.Sum(s, s))
+
+	// y3 = M * (S - x3) - 8 * y ^ 4
+	y3 := new(FieldElement).Sub(s, x3)
+	y3.Mul(y3, m).Sub(y3, t.Square(yPwr2).MulInt(t, big.NewInt(8)))
+
+	// z3 = 2 * y * z
+	z3 := new(FieldElement).Mul(p.y, p.z)
+	z3.Sum(z3, z3)
+
+	return &JacobianPoint{a: p.a, b: p.b, x: x3, y: y3, z: z3}
+}
+
+func (p *JacobianPoint) Add(other *JacobianPoint) *JacobianPoint {
+	if !p.a.EqualTo(other.a) || !p.b.EqualTo(other.b) {
+		panic("points are not on the same curve")
+	}
+
+	if p.IsInfinity() {
+		return other
+	} else if other.IsInfinity() {
+		return p
+	}
+
+	// bring both points to the same denominator
+	var (
+		z1Pwr2 = new(FieldElement).Square(p.z)
+		z2Pwr2 = new(FieldElement).Square(other.z)
+		u1     = new(FieldElement).Mul(p.x, z2Pwr2)
+		u2     = new(FieldElement).Mul(other.x, z1Pwr2)
+		s1     = new(FieldElement).Mul(z2Pwr2, other.z)
+		s2     = new(FieldElement).Mul(z1Pwr2, p.z)
+	)
+
+	s1.Mul(s1, p.y)
+	s2.Mul(s2, other.y)
+
+	if u1.EqualTo(u2) {
+		if !s1.EqualTo(s2) {
+			return newJacobianInfinity(p.a, p.b)
+		}
+		return p.Double()
+	}
+
+	// H = u2 - u1, R = s2 - s1
+	// the squares of z are not needed anymore, reuse them
+	h := u2.Sub(u2, u1)
+	r := s2.Sub(s2, s1)
+	hPwr2 := z1Pwr2.Square(h)
+	hPwr3 := z2Pwr2.Mul(hPwr2, h)
+	u1hPwr2 := u1.Mul(u1, hPwr2)
+
+	// x3 = R ^ 2 - H ^ 3 - 2 * u1 * H ^ 2
+	x3 := new(FieldElement).Square(r)
+	x3.Sub(x3, hPwr3).Sub(x3, u1hPwr2).Sub(x3, u1hPwr2)
+
+	// y3 = R * (u1 * H ^ 2 - x3) - s1 * H ^ 3
+	y3 := new(FieldElement).Sub(u1hPwr2, x3)
+	y3.Mul(y3, r).Sub(y3, s1.Mul(s1, hPwr3))
+
+	// z3 = H * z1 * z2
+	z3 := new(FieldElement).Mul(h, p.z)
+	z3.Mul(z3, other.z)
+
+	return &JacobianPoint{a: p.a, b: p.b, x: x3, y: y3, z: z3}
+}
+
+// ScalarMul uses the same binary expansion as Point.ScalarMul
+// but never leaves jacobian coordinates
+func (p *JacobianPoint) ScalarMul(s *big.Int) *JacobianPoint {
+	if s == nil {
+		panic("scalar cannot be nil")
+	}
+
+	curr := p
+	result := newJacobianInfinity(p.a, p.b)
+	for i := 0; i < s.BitLen(); i++ {
+		if s.Bit(i) == 1 {
+			result = result.Add(curr)
+		}
+
+		curr = curr.Double()
+	}
+
+	return result
+}
+
+// ToAffine converts back to a Point, paying for one field inversion.
+// Use NormalizePoints to convert many points at once
+func (p *JacobianPoint) ToAffine() *Point {
+	if p.IsInfinity() {
+		return NewIdentityPoint(p.a, p.b)
+	}
+
+	return p.toAffine(p.z.Inverse())
+}
+
+func (p *JacobianPoint) toAffine(zInv *FieldElement) *Point {
+	zInvPwr2 := new(FieldElement).Square(zInv)
+	zInvPwr3 := new(FieldElement).Mul(zInvPwr2, zInv)
+
+	return &Point{
+		a: p.a,
+		b: p.b,
+		x: zInvPwr2.Mul(p.x, zInvPwr2),
+		y: zInvPwr3.Mul(p.y, zInvPwr3),
+	}
+}
+
+func (p *JacobianPoint) String() string {
+	return fmt.Sprintf("JacobianPoint(x: %s, y: %s, z: %s, a: %s, b: %s)",
+		p.x.String(), p.y.String(), p.z.String(), p.a.String(), p.b.String())
+}
+
+// NormalizePoints converts every jacobian point to its affine form
+// sharing a single field inversion through BatchInverse
+func NormalizePoints(points []*JacobianPoint) []*Point {
+	zs := make([]*FieldElement, 0, len(points))
+	for _, p := range points {
+		if !p.IsInfinity() {
+			zs = append(zs, p.z)
+		}
+	}
+
+	zInvs := BatchInverse(zs)
+	affine := make([]*Point, len(points))
+	for i, p := range points {
+		if p.IsInfinity() {
+			affine[i] = NewIdentityPoint(p.a, p.b)
+			continue
+		}
+
+		affine[i] = p.toAffine(zInvs[0])
+		zInvs = zInvs[1:]
+	}
+
+	return affine
+}
@@ -0,0 +1,90 @@
+package ecc_test
+
+import (
+	"ecc"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJacobianPointAddition(t *testing.T) {
+	order := big.NewInt(223)
+	var a, b = ecc.NewFieldElement(order, big.NewInt(0)), ecc.NewFieldElement(order, big.NewInt(7))
+
+	x1 := ecc.NewFieldElement(order, big.NewInt(192))
+	y1 := ecc.NewFieldElement(order, big.NewInt(105))
+
+	p1 := ecc.NewPoint(x1, y1, a, b)
+	p2 := ecc.NewPoint(
+		ecc.NewFieldElement(order, big.NewInt(17)), ecc.NewFieldElement(order, big.NewInt(56)), a, b)
+
+	j1, j2 := ecc.NewJacobianPoint(p1), ecc.NewJacobianPoint(p2)
+
+	require.True(t, p1.Add(p2).EqualTo(j1.Add(j2).ToAffine()))
+	require.True(t, p1.Add(p1).EqualTo(j1.Add(j1).ToAffine()))
+	require.True(t, p1.Add(p1).EqualTo(j1.Double().ToAffine()))
+
+	// (x, y) + (x, -y) should result in the point at infinity
+	minusP1 := ecc.NewJacobianPoint(ecc.NewPoint(x1, y1.Negate(), a, b))
+	require.True(t, j1.Add(minusP1).IsInfinity())
+	require.Equal(t, ecc.NewIdentityPoint(a, b), j1.Add(minusP1).ToAffine())
+
+	id := ecc.NewJacobianPoint(ecc.NewIdentityPoint(a, b))
+	require.True(t, p1.EqualTo(id.Add(j1).ToAffine()))
+	require.True(t, p1.EqualTo(j1.Add(id).ToAffine()))
+}
+
+func TestJacobianPointScalarMul(t *testing.T) {
+	// curve with a non-zero a coefficient
+	order := big.NewInt(233)
+	var a, b = ecc.NewFieldElement(order, big.NewInt(5)), ecc.NewFieldElement(order, big.NewInt(7))
+
+	p := ecc.NewPoint(
+		ecc.NewFieldElement(order, big.NewInt(18)), ecc.NewFieldElement(order, big.NewInt(77)), a, b)
+	j := ecc.NewJacobianPoint(p)
+
+	for s := int64(1); s < 50; s++ {
+		expected := p.ScalarMul(big.NewInt(s))
+		actual := j.ScalarMul(big.NewInt(s)).ToAffine()
+		require.Equal(t, expected.String(), actual.String(), "scalar %d", s)
+	}
+
+	secret := new(big.Int)
+	secret.SetString("deadbeef54321", 16)
+	require.True(t,
+		ecc.BitcoingGenPoint.ScalarMul(secret).EqualTo(
+			ecc.NewJacobianPoint(ecc.BitcoingGenPoint).ScalarMul(secret).ToAffine(),
+		),
+	)
+}
+
+func TestNormalizePoints(t *testing.T) {
+	g := ecc.NewJacobianPoint(ecc.BitcoingGenPoint)
+
+	points := []*ecc.JacobianPoint{g.ScalarMul(big.NewInt(0))}
+	curr := g
+	for i := 0; i < 16; i++ {
+		points = append(points, curr)
+		curr = curr.Add(g)
+	}
+
+	affine := ecc.NormalizePoints(points)
+	require.Len(t, affine, len(points))
+
+	require.Equal(t, ecc.S256Point(nil, nil), affine[0])
+	for i := 1; i < len(points); i++ {
+		require.True(t, ecc.BitcoingGenPoint.ScalarMul(big.NewInt(int64(i))).EqualTo(affine[i]))
+	}
+}
+
+func BenchmarkJacobianPointScalarMul(b *testing.B) {
+	secret := new(big.Int)
+	secret.SetString("deadbeef54321deadbeef54321deadbeef54321deadbeef54321", 16)
+	g := ecc.NewJacobianPoint(ecc.BitcoingGenPoint)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g.ScalarMul(secret).ToAffine()
+	}
+}
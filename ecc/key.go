@@ -26,6 +26,11 @@ func (p *PrivateKey) PublicKey() *Point {
 	return p.pubKey
 }
 
+// Secret returns a copy of the secret scalar
+func (p *PrivateKey) Secret() *big.Int {
+	return big.NewInt(0).Set(p.secret)
+}
+
 func (p *PrivateKey) Sign(z *big.Int) *Signature {
 	k, err := rand.Int(rand.Reader, BitcoinN)
 	if err != nil {
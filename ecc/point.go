@@ -1,6 +1,8 @@
 package ecc
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -16,6 +18,8 @@ const (
 	Exp
 )
 
+var ErrNotOnCurve = errors.New("point is not on the curve")
+
 type Point struct {
 	// coefficients of the curve
 	a *FieldElement
@@ -94,11 +98,18 @@ func NewPoint(x, y, a, b *FieldElement) *Point {
 	}
 }
 
+// EqualTo reports whether both points are on the same curve and are the
+// same point, the point at infinity included
 func (p *Point) EqualTo(other *Point) bool {
-	return p.a.EqualTo(other.a) &&
-		p.b.EqualTo(other.b) &&
-		p.x.EqualTo(other.x) &&
-		p.y.EqualTo(other.y)
+	if !p.a.EqualTo(other.a) || !p.b.EqualTo(other.b) {
+		return false
+	}
+
+	if p.IsInfinity() || other.IsInfinity() {
+		return p.IsInfinity() == other.IsInfinity()
+	}
+
+	return p.X().Cmp(other.X()) == 0 && p.Y().Cmp(other.Y()) == 0
 }
 
 func (p *Point) NotEqual(other *Point) bool {
@@ -117,44 +128,118 @@ func (p *Point) Add(other *Point) *Point {
 	}
 
 	// points are on the vertical A(x, y) B (x, -y)
-	zero := NewFieldElement(p.x.order, big.NewInt(0))
-	if p.x.EqualTo(other.x) && performOp(p.y, other.y, nil, Add).EqualTo(zero) {
+	if p.x.EqualTo(other.x) && new(FieldElement).Sum(p.y, other.y).num.Sign() == 0 {
 		return NewIdentityPoint(p.a, p.b)
 	}
 
 	// find the slope of line AB
 	var (
-		x1, y1                 = p.x, p.y
-		x2, y2                 = other.x, other.y
-		numerator, denominator = zero, zero
+		x1, y1             = p.x, p.y
+		x2, y2             = other.x, other.y
+		slope, denominator = new(FieldElement), new(FieldElement)
 	)
 
 	if x1.EqualTo(x2) && y1.EqualTo(y2) {
 		// slope = [3 * (x ^ 2) + a ] / 2y
-		numerator = performOp(
-			performOp(performOp(x1, nil, big.NewInt(2), Exp), nil, big.NewInt(3), Mul),
-			p.a, nil, Add)
-		denominator = performOp(y1, nil, big.NewInt(2), Mul)
+		slope.Square(x1).MulInt(slope, big.NewInt(3)).Sum(slope, p.a)
+		denominator.Sum(y1, y1)
 	} else {
-		numerator = performOp(y2, y1, nil, Sub)
-		denominator = performOp(x2, x1, nil, Sub)
+		slope.Sub(y2, y1)
+		denominator.Sub(x2, x1)
 	}
 
-	slope := performOp(numerator, denominator, nil, Div)
-	slopePwr2 := performOp(slope, nil, big.NewInt(2), Exp)
-	x3 := performOp(performOp(slopePwr2, x1, nil, Sub), x2, nil, Sub)
-	x3Subx1 := performOp(x3, x1, nil, Sub)
-	y3 := performOp(performOp(slope, x3Subx1, nil, Mul), y1, nil, Add)
-	minusY3 := performOp(y3, nil, big.NewInt(-1), Mul)
+	slope.Div(slope, denominator)
+
+	// x3 = slope ^ 2 - x1 - x2
+	x3 := new(FieldElement).Square(slope)
+	x3.Sub(x3, x1).Sub(x3, x2)
+
+	// y3 = slope * (x1 - x3) - y1
+	y3 := new(FieldElement).Sub(x1, x3)
+	y3.Mul(y3, slope).Sub(y3, y1)
 
 	return &Point{
 		x: x3,
-		y: minusY3,
+		y: y3,
 		a: p.a,
 		b: p.b,
 	}
 }
 
+// Sub returns p - other
+func (p *Point) Sub(other *Point) *Point {
+	return p.Add(other.Neg())
+}
+
+// Double returns p + p
+func (p *Point) Double() *Point {
+	return p.Add(p)
+}
+
+// Neg returns the point with the same x and the opposite y, the
+// negation of the point at infinity is itself
+func (p *Point) Neg() *Point {
+	if p.IsInfinity() {
+		return p
+	}
+
+	return &Point{a: p.a, b: p.b, x: p.x, y: p.y.Negate()}
+}
+
+func (p *Point) IsInfinity() bool {
+	return p.x == nil
+}
+
+// IsOnCurve reports whether p satisfies the equation of its curve,
+// the point at infinity is always on the curve
+func (p *Point) IsOnCurve() bool {
+	if p.IsInfinity() {
+		return true
+	}
+
+	return CheckIsOnCurve(p.x, p.y, p.a, p.b)
+}
+
+// X returns a copy of the x coordinate reduced to [0, order),
+// nil for the point at infinity
+func (p *Point) X() *big.Int {
+	return coordinate(p.x)
+}
+
+// Y returns a copy of the y coordinate reduced to [0, order),
+// nil for the point at infinity
+func (p *Point) Y() *big.Int {
+	return coordinate(p.y)
+}
+
+// XBytes returns the big endian x coordinate padded to the size of
+// the field, nil for the point at infinity
+func (p *Point) XBytes() []byte {
+	return coordinateBytes(p.x)
+}
+
+// YBytes returns the big endian y coordinate padded to the size of
+// the field, nil for the point at infinity
+func (p *Point) YBytes() []byte {
+	return coordinateBytes(p.y)
+}
+
+func coordinate(f *FieldElement) *big.Int {
+	if f == nil {
+		return nil
+	}
+
+	return big.NewInt(0).Mod(f.int(), f.order)
+}
+
+func coordinateBytes(f *FieldElement) []byte {
+	if f == nil {
+		return nil
+	}
+
+	return coordinate(f).FillBytes(make([]byte, fieldElementSize(f.order)))
+}
+
 // ScalarMul uses binary expansion to execute a optimized
 // multiplication mainly with big scalar values
 func (p *Point) ScalarMul(s *big.Int) *Point {
@@ -185,6 +270,9 @@ func (p *Point) Verify(z *FieldElement, sig *Signature) bool {
 	u := z.Multiply(sInv)
 	v := sig.r.Multiply(sInv)
 	bigR := (BitcoingGenPoint.ScalarMul(u.num)).Add(p.ScalarMul(v.num))
+	if bigR.IsInfinity() {
+		return false
+	}
 
 	return bigR.x.num.Cmp(sig.r.num) == 0
 }
@@ -234,56 +322,59 @@ func FromSec(input io.Reader) (*Point, error) {
 }
 
 func fromUncompressedSec(input io.Reader) (*Point, error) {
-	x := make([]byte, 32)
-	y := make([]byte, 32)
-
-	_, err := input.Read(x)
+	x, err := readCoordinate(input)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = input.Read(y)
+	y, err := readCoordinate(input)
 	if err != nil {
 		return nil, err
 	}
 
-	return S256Point(big.NewInt(0).SetBytes(x), big.NewInt(0).SetBytes(y)), nil
+	if !CheckIsOnCurve(x, y, S256Field(big.NewInt(0)), S256Field(big.NewInt(7))) {
+		return nil, ErrNotOnCurve
+	}
+
+	return S256Point(x.num, y.num), nil
 }
 
 func fromEvenCompressed(input io.Reader) (*Point, error) {
-	x := make([]byte, 32)
-	_, err := input.Read(x)
+	x, err := readCoordinate(input)
 	if err != nil {
 		return nil, err
 	}
 
-	xInt := big.NewInt(0).SetBytes(x)
-	ySq := S256Field(xInt).Power(big.NewInt(3)).Add(S256Field(big.NewInt(7)))
-	y := ySq.Sqrt()
-
-	if big.NewInt(0).Mod(y.num, big.NewInt(2)).Cmp(big.NewInt(0)) == 0 {
-		return S256Point(big.NewInt(0).SetBytes(x), y.num), nil
+	p, ok := liftX(x)
+	if !ok {
+		return nil, ErrNotOnCurve
 	}
 
-	return S256Point(big.NewInt(0).SetBytes(x), y.Negate().num), nil
+	return p, nil
 }
 
 func fromOddCompressed(input io.Reader) (*Point, error) {
-	x := make([]byte, 32)
-	_, err := input.Read(x)
+	p, err := fromEvenCompressed(input)
 	if err != nil {
 		return nil, err
 	}
 
-	xInt := big.NewInt(0).SetBytes(x)
-	ySq := S256Field(xInt).Power(big.NewInt(3)).Add(S256Field(big.NewInt(7)))
-	y := ySq.Sqrt()
+	p.y = new(FieldElement).Neg(p.y)
+	return p, nil
+}
+
+func readCoordinate(input io.Reader) (*FieldElement, error) {
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(input, buf); err != nil {
+		return nil, err
+	}
 
-	if big.NewInt(0).Mod(y.num, big.NewInt(2)).Cmp(big.NewInt(0)) == 0 {
-		return S256Point(big.NewInt(0).SetBytes(x), y.Negate().num), nil
+	coordinate := big.NewInt(0).SetBytes(buf)
+	if coordinate.Cmp(BitcoinOrder) >= 0 {
+		return nil, fmt.Errorf("coordinate is not a field element")
 	}
 
-	return S256Point(big.NewInt(0).SetBytes(x), y.num), nil
+	return S256Field(coordinate), nil
 }
 
 func (p *Point) Sec(compressed bool) string {
@@ -299,3 +390,36 @@ func (p *Point) Sec(compressed bool) string {
 		return fmt.Sprintf("03%064x", p.x.num)
 	}
 }
+
+// liftX returns the point with the given x coordinate and an even y,
+// reporting false when x ^ 3 + 7 is not a square
+func liftX(x *FieldElement) (*Point, bool) {
+	ySq := new(FieldElement).Square(x)
+	ySq.Mul(ySq, x).Sum(ySq, S256Field(big.NewInt(7)))
+
+	y, ok := sqrt(ySq)
+	if !ok {
+		return nil, false
+	}
+
+	if y.num.Bit(0) == 1 {
+		y.Neg(y)
+	}
+
+	return &Point{
+		a: S256Field(big.NewInt(0)),
+		b: S256Field(big.NewInt(7)),
+		x: x,
+		y: y,
+	}, true
+}
+
+// compressedSec returns the 33 bytes of the compressed SEC format
+func (p *Point) compressedSec() []byte {
+	sec, err := hex.DecodeString(p.Sec(true))
+	if err != nil {
+		panic(err)
+	}
+
+	return sec
+}
@@ -207,4 +207,101 @@ func TestPointFromSec(t *testing.T) {
 	require.NoError(t, err)
 
 	require.True(t, p1.EqualTo(sameP1))
+
+	// x = 5 has no y on secp256k1
+	notOnCurve := make([]byte, 33)
+	notOnCurve[0], notOnCurve[32] = 0x02, 0x05
+	_, err = ecc.FromSec(bytes.NewReader(notOnCurve))
+	require.ErrorIs(t, err, ecc.ErrNotOnCurve)
+
+	_, err = ecc.FromSec(bytes.NewReader(secBinCompressed.Bytes()[:20]))
+	require.Error(t, err)
+}
+
+func TestPointGroupAPI(t *testing.T) {
+	a, b := toyCurve(223, 0, 7)
+	p := toyPoint(223, 47, 71, a, b)
+	q := toyPoint(223, 17, 56, a, b)
+	inf := ecc.NewIdentityPoint(a, b)
+
+	require.Equal(t, big.NewInt(47), p.X())
+	require.Equal(t, big.NewInt(71), p.Y())
+	require.Equal(t, []byte{47}, p.XBytes())
+	require.Equal(t, []byte{71}, p.YBytes())
+	require.False(t, p.IsInfinity())
+	require.True(t, p.IsOnCurve())
+
+	// accessors return copies
+	p.X().SetInt64(1)
+	require.Equal(t, big.NewInt(47), p.X())
+
+	neg := p.Neg()
+	require.Equal(t, big.NewInt(47), neg.X())
+	require.Equal(t, big.NewInt(223-71), neg.Y())
+	require.True(t, neg.IsOnCurve())
+	require.True(t, p.Add(neg).IsInfinity())
+	require.True(t, p.Sub(p).IsInfinity())
+
+	require.True(t, p.Double().EqualTo(p.ScalarMul(big.NewInt(2))))
+	require.True(t, p.Add(q).Sub(q).EqualTo(p))
+	require.True(t, p.Sub(q).EqualTo(p.Add(q.Neg())))
+	require.True(t, p.ScalarMul(big.NewInt(21)).IsInfinity())
+	require.False(t, p.EqualTo(q))
+	require.True(t, p.NotEqual(neg))
+
+	// coordinates are compared once reduced
+	unreduced := toyPoint(223, 47-223, 71-223, a, b)
+	require.True(t, p.EqualTo(unreduced))
+	require.Equal(t, p.X(), unreduced.X())
+
+	// points of another curve are never equal
+	a2, b2 := toyCurve(223, 0, 5)
+	require.False(t, inf.EqualTo(ecc.NewIdentityPoint(a2, b2)))
+}
+
+func TestPointIdentity(t *testing.T) {
+	a, b := toyCurve(223, 0, 7)
+	p := toyPoint(223, 47, 71, a, b)
+	inf := ecc.NewIdentityPoint(a, b)
+
+	require.True(t, inf.IsInfinity())
+	require.True(t, inf.IsOnCurve())
+	require.Nil(t, inf.X())
+	require.Nil(t, inf.Y())
+	require.Nil(t, inf.XBytes())
+	require.Nil(t, inf.YBytes())
+
+	require.True(t, inf.EqualTo(ecc.NewIdentityPoint(a, b)))
+	require.False(t, inf.EqualTo(p))
+	require.False(t, p.EqualTo(inf))
+	require.True(t, inf.NotEqual(p))
+
+	require.True(t, inf.Neg().IsInfinity())
+	require.True(t, inf.Double().IsInfinity())
+	require.True(t, inf.Sub(inf).IsInfinity())
+	require.True(t, p.Sub(inf).EqualTo(p))
+	require.True(t, inf.Sub(p).EqualTo(p.Neg()))
+
+	// a point of order two doubles to infinity
+	a3, b3 := toyCurve(223, 0, 1)
+	two := toyPoint(223, 222, 0, a3, b3)
+	require.True(t, two.Neg().EqualTo(two))
+	require.True(t, two.Double().IsInfinity())
+
+	g := ecc.BitcoingGenPoint
+	require.True(t, g.IsOnCurve())
+	require.Len(t, g.XBytes(), 32)
+	require.Equal(t, g.Sec(true)[2:], fmt.Sprintf("%x", g.XBytes()))
+	require.True(t, g.ScalarMul(ecc.BitcoinN).EqualTo(ecc.S256Point(nil, nil)))
+	require.True(t, g.Sub(g).EqualTo(ecc.S256Point(nil, nil)))
+}
+
+func BenchmarkPointScalarMul(b *testing.B) {
+	secret := new(big.Int)
+	secret.SetString("deadbeef54321deadbeef54321deadbeef54321deadbeef54321", 16)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ecc.BitcoingGenPoint.ScalarMul(secret)
+	}
 }
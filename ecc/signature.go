@@ -1,6 +1,12 @@
 package ecc
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrInvalidDER = errors.New("invalid DER signature")
 
 type Signature struct {
 	r *FieldElement
@@ -33,3 +39,68 @@ func (s *Signature) Der() []byte {
 	encodedSection = append([]byte{0x30, byte(len(encodedSection))}, encodedSection...)
 	return encodedSection
 }
+
+// R returns a copy of the r value of the signature
+func (s *Signature) R() *big.Int {
+	return big.NewInt(0).Set(s.r.num)
+}
+
+// S returns a copy of the s value of the signature
+func (s *Signature) S() *big.Int {
+	return big.NewInt(0).Set(s.s.num)
+}
+
+// ParseDER parses a strict DER encoded signature, as enforced by
+// BIP66, both values must be in the range [1, n)
+func ParseDER(der []byte) (*Signature, error) {
+	if len(der) < 8 || len(der) > 72 {
+		return nil, fmt.Errorf("%w: invalid length %d", ErrInvalidDER, len(der))
+	}
+
+	if der[0] != 0x30 || int(der[1]) != len(der)-2 {
+		return nil, fmt.Errorf("%w: invalid sequence header", ErrInvalidDER)
+	}
+
+	r, rest, err := parseDERInteger(der[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	s, rest, err := parseDERInteger(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing bytes", ErrInvalidDER)
+	}
+
+	return NewSignature(NewFieldElement(BitcoinN, r), NewFieldElement(BitcoinN, s)), nil
+}
+
+func parseDERInteger(input []byte) (*big.Int, []byte, error) {
+	if len(input) < 3 || input[0] != 0x02 {
+		return nil, nil, fmt.Errorf("%w: expected an integer", ErrInvalidDER)
+	}
+
+	length := int(input[1])
+	if length == 0 || length > 33 || len(input) < 2+length {
+		return nil, nil, fmt.Errorf("%w: invalid integer length", ErrInvalidDER)
+	}
+
+	value := input[2 : 2+length]
+	if value[0]&0x80 != 0 {
+		return nil, nil, fmt.Errorf("%w: negative integer", ErrInvalidDER)
+	}
+
+	if length > 1 && value[0] == 0x00 && value[1]&0x80 == 0 {
+		return nil, nil, fmt.Errorf("%w: integer is not minimally encoded", ErrInvalidDER)
+	}
+
+	n := big.NewInt(0).SetBytes(value)
+	if n.Sign() == 0 || n.Cmp(BitcoinN) >= 0 {
+		return nil, nil, fmt.Errorf("%w: integer out of range", ErrInvalidDER)
+	}
+
+	return n, input[2+length:], nil
+}
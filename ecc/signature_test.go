@@ -2,6 +2,7 @@ package ecc_test
 
 import (
 	"ecc"
+	"encoding/hex"
 	"math/big"
 	"testing"
 
@@ -24,3 +25,35 @@ func TestDerEncoding(t *testing.T) {
 
 	require.Equal(t, expected.Bytes(), der)
 }
+
+func TestParseDER(t *testing.T) {
+	der, err := hex.DecodeString("3045022037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c60221008ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec")
+	require.NoError(t, err)
+
+	sig, err := ecc.ParseDER(der)
+	require.NoError(t, err)
+	require.Equal(t, der, sig.Der())
+	require.Equal(t, "37206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c6", sig.R().Text(16))
+	require.Equal(t, "8ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec", sig.S().Text(16))
+
+	invalid := map[string]string{
+		"empty":            "",
+		"wrong sequence":   "3145022037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c60221008ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec",
+		"wrong length":     "3046022037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c60221008ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec",
+		"negative s":       "3044022037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c602208ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec",
+		"padded r":         "304602210037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c60221008ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec",
+		"trailing bytes":   "3046022037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c60221008ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec00",
+		"zero r":           "3026020100022100" + "8ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec",
+		"missing s marker": "3045022037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c60321008ca63759c1157ebeaec0d03cecca119fc9a75bf8e6d0fa65c841c8e2738cdaec",
+	}
+
+	for name, h := range invalid {
+		t.Run(name, func(t *testing.T) {
+			der, err := hex.DecodeString(h)
+			require.NoError(t, err)
+
+			_, err = ecc.ParseDER(der)
+			require.ErrorIs(t, err, ecc.ErrInvalidDER)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package ecc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"base58"
+	"chaincfg"
+)
+
+// Wallet Import Format, the Base58Check encoding of the network private
+// key prefix, the 32 bytes secret and a 0x01 flag when the public key
+// is used compressed
+
+const wifCompressedFlag = 0x01
+
+var ErrInvalidWIF = errors.New("invalid WIF private key")
+
+// WIF is a decoded private key, it remembers the network and whether
+// the exporting wallet used the compressed public key
+type WIF struct {
+	*PrivateKey
+	Compressed bool
+	Net        *chaincfg.Params
+}
+
+// WIF encodes p for net, compressed tells the importing wallet to use
+// the compressed public key to derive addresses
+func (p *PrivateKey) WIF(compressed bool, net *chaincfg.Params) string {
+	payload := make([]byte, 1+privateKeySize, 2+privateKeySize)
+	payload[0] = net.PrivateKeyID
+	p.secret.FillBytes(payload[1:])
+
+	if compressed {
+		payload = append(payload, wifCompressedFlag)
+	}
+
+	return base58.CheckEncode(payload)
+}
+
+// ParseWIF decodes a WIF string of any registered network, the first
+// network with its prefix is reported as the test networks share it
+func ParseWIF(s string) (*WIF, error) {
+	payload, err := base58.CheckDecode(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidWIF, err)
+	}
+
+	var compressed bool
+	switch {
+	case len(payload) == 1+privateKeySize:
+	case len(payload) == 2+privateKeySize && payload[len(payload)-1] == wifCompressedFlag:
+		compressed = true
+	default:
+		return nil, fmt.Errorf("%w: unexpected %d bytes payload", ErrInvalidWIF, len(payload))
+	}
+
+	var net *chaincfg.Params
+	for _, params := range chaincfg.Networks() {
+		if params.PrivateKeyID == payload[0] {
+			net = params
+			break
+		}
+	}
+
+	if net == nil {
+		return nil, fmt.Errorf("%w: %w: prefix %#02x", ErrInvalidWIF, chaincfg.ErrUnknownNet, payload[0])
+	}
+
+	secret := big.NewInt(0).SetBytes(payload[1 : 1+privateKeySize])
+	if secret.Sign() == 0 || secret.Cmp(BitcoinN) >= 0 {
+		return nil, fmt.Errorf("%w: private key must be in the range [1, n)", ErrInvalidWIF)
+	}
+
+	return &WIF{PrivateKey: NewPrivateKey(secret), Compressed: compressed, Net: net}, nil
+}
+
+// String encodes the key back with the preferences it was decoded with
+func (w *WIF) String() string {
+	return w.PrivateKey.WIF(w.Compressed, w.Net)
+}
+
+// PublicKeySec returns the SEC encoding of the public key in the form
+// addresses should be derived from
+func (w *WIF) PublicKeySec() string {
+	return w.PublicKey().Sec(w.Compressed)
+}
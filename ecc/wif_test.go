@@ -0,0 +1,69 @@
+package ecc_test
+
+import (
+	"base58"
+	"chaincfg"
+	"ecc"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWIF(t *testing.T) {
+	wikiKey, _ := big.NewInt(0).SetString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", 16)
+
+	vectors := []struct {
+		secret     *big.Int
+		compressed bool
+		net        *chaincfg.Params
+		wif        string
+	}{
+		{big.NewInt(1), true, &chaincfg.MainNetParams, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"},
+		{big.NewInt(1), false, &chaincfg.MainNetParams, "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf"},
+		{big.NewInt(1), true, &chaincfg.TestNet3Params, "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA"},
+		{wikiKey, false, &chaincfg.MainNetParams, "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"},
+		{wikiKey, true, &chaincfg.MainNetParams, "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"},
+	}
+
+	for _, v := range vectors {
+		key := ecc.NewPrivateKey(v.secret)
+		require.Equal(t, v.wif, key.WIF(v.compressed, v.net))
+
+		decoded, err := ecc.ParseWIF(v.wif)
+		require.NoError(t, err, v.wif)
+		require.Equal(t, v.secret, decoded.Secret())
+		require.Equal(t, v.compressed, decoded.Compressed)
+		require.Same(t, v.net, decoded.Net)
+		require.Equal(t, v.wif, decoded.String())
+		require.Equal(t, key.PublicKey().Sec(v.compressed), decoded.PublicKeySec())
+	}
+}
+
+func TestParseWIFErrors(t *testing.T) {
+	payload := func(prefix byte, secret []byte, suffix ...byte) string {
+		return base58.CheckEncode(append(append([]byte{prefix}, secret...), suffix...))
+	}
+
+	one := make([]byte, 32)
+	one[31] = 1
+
+	invalid := map[string]string{
+		"checksum":       "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWm",
+		"character":      "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoW0",
+		"short":          payload(0x80, one[1:]),
+		"long":           payload(0x80, one, 0x01, 0x00),
+		"flag":           payload(0x80, one, 0x02),
+		"zero secret":    payload(0x80, make([]byte, 32)),
+		"secret over n":  payload(0x80, ecc.BitcoinN.Bytes(), 0x01),
+		"unknown prefix": payload(0xb0, one, 0x01),
+	}
+
+	for name, s := range invalid {
+		_, err := ecc.ParseWIF(s)
+		require.ErrorIs(t, err, ecc.ErrInvalidWIF, name)
+	}
+
+	_, err := ecc.ParseWIF(invalid["unknown prefix"])
+	require.ErrorIs(t, err, chaincfg.ErrUnknownNet)
+}
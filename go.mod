@@ -2,6 +2,52 @@ module main
 
 go 1.23.1
 
+replace address => ./address
+
+replace amount => ./amount
+
+replace audit => ./audit
+
+replace base58 => ./base58
+
+replace bech32 => ./bech32
+
+replace bip38 => ./bip38
+
+replace chaincfg => ./chaincfg
+
 replace ecc => ./ecc
 
-require ecc v0.0.0-00010101000000-000000000000
+replace hashes => ./hashes
+
+replace silentpayments => ./silentpayments
+
+replace transaction => ./transaction
+
+replace twoparty => ./twoparty
+
+replace vss => ./vss
+
+replace wire => ./wire
+
+require (
+	address v0.0.0-00010101000000-000000000000
+	amount v0.0.0-00010101000000-000000000000
+	audit v0.0.0-00010101000000-000000000000
+	base58 v0.0.0-00010101000000-000000000000
+	bech32 v0.0.0-00010101000000-000000000000
+	bip38 v0.0.0-00010101000000-000000000000
+	chaincfg v0.0.0-00010101000000-000000000000
+	ecc v0.0.0-00010101000000-000000000000
+	hashes v0.0.0-00010101000000-000000000000
+	silentpayments v0.0.0-00010101000000-000000000000
+	transaction v0.0.0-00010101000000-000000000000
+	twoparty v0.0.0-00010101000000-000000000000
+	vss v0.0.0-00010101000000-000000000000
+	wire v0.0.0-00010101000000-000000000000
+)
+
+require (
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
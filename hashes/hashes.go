@@ -0,0 +1,64 @@
+package hashes
+
+import (
+	"crypto/sha256"
+	"hash"
+)
+
+// Hashes used across Bitcoin: HASH160 for keys and scripts in
+// addresses, HASH256 for transactions, blocks and checksums
+
+const (
+	Hash160Size = RIPEMD160Size
+	Hash256Size = sha256.Size
+)
+
+// Hash160 returns RIPEMD160(SHA256(data))
+func Hash160(data []byte) [Hash160Size]byte {
+	h := sha256.Sum256(data)
+	return RIPEMD160(h[:])
+}
+
+// Hash256 returns SHA256(SHA256(data))
+func Hash256(data []byte) [Hash256Size]byte {
+	h := sha256.Sum256(data)
+	return sha256.Sum256(h[:])
+}
+
+// doubleHash streams into inner and hashes its digest with outer
+type doubleHash struct {
+	inner hash.Hash
+	outer func() hash.Hash
+}
+
+// NewHash160 returns a streaming HASH160
+func NewHash160() hash.Hash {
+	return &doubleHash{inner: sha256.New(), outer: NewRIPEMD160}
+}
+
+// NewHash256 returns a streaming HASH256
+func NewHash256() hash.Hash {
+	return &doubleHash{inner: sha256.New(), outer: sha256.New}
+}
+
+func (d *doubleHash) Write(p []byte) (int, error) {
+	return d.inner.Write(p)
+}
+
+func (d *doubleHash) Sum(in []byte) []byte {
+	outer := d.outer()
+	outer.Write(d.inner.Sum(nil))
+	return outer.Sum(in)
+}
+
+func (d *doubleHash) Reset() {
+	d.inner.Reset()
+}
+
+func (d *doubleHash) Size() int {
+	return d.outer().Size()
+}
+
+func (d *doubleHash) BlockSize() int {
+	return d.inner.BlockSize()
+}
@@ -0,0 +1,100 @@
+package hashes_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hashes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash160(t *testing.T) {
+	// compressed public key of the private key 1
+	key, err := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	require.NoError(t, err)
+
+	digest := hashes.Hash160(key)
+	require.Equal(t, "751e76e8199196d454941c45d1b3a323f1433bd6", hex.EncodeToString(digest[:]))
+
+	h := hashes.NewHash160()
+	require.Equal(t, hashes.Hash160Size, h.Size())
+	h.Write(key[:10])
+	h.Write(key[10:])
+	require.Equal(t, digest[:], h.Sum(nil))
+
+	h.Reset()
+	h.Write(key)
+	require.Equal(t, digest[:], h.Sum(nil))
+}
+
+func TestHash256(t *testing.T) {
+	digest := hashes.Hash256(nil)
+	require.Equal(t, "5df6e0e2761359d30a8275058e299fcc0381534545f55cf43e41983f5d4c9456", hex.EncodeToString(digest[:]))
+
+	// the genesis block header hashes to its reversed block hash
+	header, err := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c")
+	require.NoError(t, err)
+
+	digest = hashes.Hash256(header)
+	for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+		digest[i], digest[j] = digest[j], digest[i]
+	}
+	require.Equal(t, "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f", hex.EncodeToString(digest[:]))
+
+	h := hashes.NewHash256()
+	require.Equal(t, hashes.Hash256Size, h.Size())
+	require.Equal(t, sha256.BlockSize, h.BlockSize())
+	h.Write(header[:40])
+	h.Write(header[40:])
+	expected := hashes.Hash256(header)
+	require.Equal(t, expected[:], h.Sum(nil))
+}
+
+func taggedReference(tag string, msg []byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+func TestTaggedHash(t *testing.T) {
+	msg := []byte("some message split in parts")
+
+	for _, tag := range []string{"BIP0340/challenge", "TapLeaf", "", "BIP0352/SharedSecret"} {
+		expected := taggedReference(tag, msg)
+
+		digest := hashes.TaggedHash(tag, msg)
+		require.Equal(t, expected, digest[:], tag)
+
+		// parts are concatenated and the cached prefix is reused
+		digest = hashes.TaggedHash(tag, msg[:4], msg[4:10], nil, msg[10:])
+		require.Equal(t, expected, digest[:], tag)
+
+		h := hashes.NewTaggedHash(tag)
+		h.Write([]byte("garbage"))
+		h.Reset()
+		h.Write(msg)
+		require.Equal(t, expected, h.Sum(nil), tag)
+	}
+}
+
+func TestTaggedHashConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				msg := []byte{byte(j)}
+				digest := hashes.TaggedHash("concurrent", msg)
+				require.Equal(t, taggedReference("concurrent", msg), digest[:])
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,175 @@
+package hashes
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// RIPEMD-160 as specified by Dobbertin, Bosselaers and Preneel. Two
+// parallel lines of 80 steps process every 64 bytes block, the words
+// of the block and of the state are little endian
+
+const (
+	RIPEMD160Size      = 20
+	RIPEMD160BlockSize = 64
+)
+
+var (
+	// message word selection of the left and right lines
+	ripemdR = [80]uint8{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+		3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+		1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+		4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+	}
+	ripemdRR = [80]uint8{
+		5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+		6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+		15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+		8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+		12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+	}
+
+	// rotation amounts of the left and right lines
+	ripemdS = [80]uint8{
+		11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+		7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+		11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+		11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+		9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+	}
+	ripemdSR = [80]uint8{
+		8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+		9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+		9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+		15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+		8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+	}
+
+	ripemdK  = [5]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e}
+	ripemdKR = [5]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000}
+)
+
+type ripemd160 struct {
+	s   [5]uint32
+	buf [RIPEMD160BlockSize]byte
+	n   int
+	len uint64
+}
+
+// NewRIPEMD160 returns a streaming RIPEMD-160 hash
+func NewRIPEMD160() hash.Hash {
+	d := new(ripemd160)
+	d.Reset()
+	return d
+}
+
+// RIPEMD160 returns the RIPEMD-160 digest of data
+func RIPEMD160(data []byte) [RIPEMD160Size]byte {
+	d := new(ripemd160)
+	d.Reset()
+	d.Write(data)
+
+	var out [RIPEMD160Size]byte
+	d.Sum(out[:0])
+	return out
+}
+
+func (d *ripemd160) Reset() {
+	d.s = [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+	d.n, d.len = 0, 0
+}
+
+func (d *ripemd160) Size() int { return RIPEMD160Size }
+
+func (d *ripemd160) BlockSize() int { return RIPEMD160BlockSize }
+
+func (d *ripemd160) Write(p []byte) (int, error) {
+	written := len(p)
+	d.len += uint64(written)
+
+	if d.n > 0 {
+		c := copy(d.buf[d.n:], p)
+		d.n += c
+		p = p[c:]
+		if d.n < RIPEMD160BlockSize {
+			return written, nil
+		}
+
+		d.block(d.buf[:])
+		d.n = 0
+	}
+
+	for len(p) >= RIPEMD160BlockSize {
+		d.block(p[:RIPEMD160BlockSize])
+		p = p[RIPEMD160BlockSize:]
+	}
+
+	d.n = copy(d.buf[:], p)
+	return written, nil
+}
+
+// Sum appends the digest to in without changing the state of d
+func (d *ripemd160) Sum(in []byte) []byte {
+	c := *d
+
+	// a 0x80 byte, zeros up to 56 bytes modulo 64 and the bit length
+	var pad [RIPEMD160BlockSize + 8]byte
+	pad[0] = 0x80
+	n := 56 - int(c.len%64)
+	if n <= 0 {
+		n += 64
+	}
+
+	binary.LittleEndian.PutUint64(pad[n:], c.len<<3)
+	c.Write(pad[:n+8])
+
+	var out [RIPEMD160Size]byte
+	for i, v := range c.s {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+
+	return append(in, out[:]...)
+}
+
+func ripemdF(j int, x, y, z uint32) uint32 {
+	switch j / 16 {
+	case 0:
+		return x ^ y ^ z
+	case 1:
+		return (x & y) | (^x & z)
+	case 2:
+		return (x | ^y) ^ z
+	case 3:
+		return (x & z) | (y & ^z)
+	default:
+		return x ^ (y | ^z)
+	}
+}
+
+func (d *ripemd160) block(p []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(p[4*i:])
+	}
+
+	a, b, c, dd, e := d.s[0], d.s[1], d.s[2], d.s[3], d.s[4]
+	ar, br, cr, dr, er := a, b, c, dd, e
+
+	for j := 0; j < 80; j++ {
+		t := bits.RotateLeft32(a+ripemdF(j, b, c, dd)+x[ripemdR[j]]+ripemdK[j/16], int(ripemdS[j])) + e
+		a, e, dd, c, b = e, dd, bits.RotateLeft32(c, 10), b, t
+
+		t = bits.RotateLeft32(ar+ripemdF(79-j, br, cr, dr)+x[ripemdRR[j]]+ripemdKR[j/16], int(ripemdSR[j])) + er
+		ar, er, dr, cr, br = er, dr, bits.RotateLeft32(cr, 10), br, t
+	}
+
+	t := d.s[1] + c + dr
+	d.s[1] = d.s[2] + dd + er
+	d.s[2] = d.s[3] + e + ar
+	d.s[3] = d.s[4] + a + br
+	d.s[4] = d.s[0] + b + cr
+	d.s[0] = t
+}
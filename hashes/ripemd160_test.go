@@ -0,0 +1,73 @@
+package hashes_test
+
+import (
+	"encoding/hex"
+	"hashes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vectors from the RIPEMD-160 specification
+var ripemdVectors = []struct {
+	msg    string
+	digest string
+}{
+	{"", "9c1185a5c5e9fc54612808977ee8f548b2258d31"},
+	{"a", "0bdc9d2d256b3ee9daae347be6f4dc835a467ffe"},
+	{"abc", "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc"},
+	{"message digest", "5d0689ef49d2fae572b881b123a85ffa21595f36"},
+	{"abcdefghijklmnopqrstuvwxyz", "f71c27109c692c1b56bbdceb5b9d2865b3708dbc"},
+	{"abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", "12a053384a9c0c88e405a06c27dcf49ada62eb2b"},
+	{"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789", "b0e20b6e3116640286ed3a87a5713079b21f5189"},
+	{strings.Repeat("1234567890", 8), "9b752e45573d4b39f4dbd3323cab82bf63326bfb"},
+	{strings.Repeat("a", 1000000), "52783243c1697bdbe16d37f97f68f08325dc1528"},
+}
+
+func TestRIPEMD160(t *testing.T) {
+	for _, v := range ripemdVectors {
+		digest := hashes.RIPEMD160([]byte(v.msg))
+		require.Equal(t, v.digest, hex.EncodeToString(digest[:]), len(v.msg))
+	}
+}
+
+func TestRIPEMD160Streaming(t *testing.T) {
+	h := hashes.NewRIPEMD160()
+	require.Equal(t, hashes.RIPEMD160Size, h.Size())
+	require.Equal(t, hashes.RIPEMD160BlockSize, h.BlockSize())
+
+	for _, v := range ripemdVectors {
+		// uneven writes cross the block boundaries at every offset
+		h.Reset()
+		msg := []byte(v.msg)
+		for i, step := 0, 1; i < len(msg); i, step = i+step, step%97+1 {
+			h.Write(msg[i:min(i+step, len(msg))])
+		}
+
+		require.Equal(t, v.digest, hex.EncodeToString(h.Sum(nil)), len(v.msg))
+
+		// Sum does not change the state
+		require.Equal(t, v.digest, hex.EncodeToString(h.Sum(nil)), len(v.msg))
+	}
+
+	// a running hash can be extended after Sum
+	h.Reset()
+	h.Write([]byte("message "))
+	h.Sum(nil)
+	h.Write([]byte("digest"))
+	require.Equal(t, "5d0689ef49d2fae572b881b123a85ffa21595f36", hex.EncodeToString(h.Sum(nil)))
+}
+
+func TestRIPEMD160PaddingBoundaries(t *testing.T) {
+	// lengths around the 56 bytes limit of a single padded block
+	for n := 50; n < 130; n++ {
+		msg := []byte(strings.Repeat("x", n))
+		digest := hashes.RIPEMD160(msg)
+
+		h := hashes.NewRIPEMD160()
+		h.Write(msg[:n/2])
+		h.Write(msg[n/2:])
+		require.Equal(t, digest[:], h.Sum(nil), n)
+	}
+}
@@ -0,0 +1,65 @@
+package hashes
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"hash"
+	"sync"
+)
+
+// BIP340 tagged hashes, SHA256(SHA256(tag) || SHA256(tag) || msg). The
+// prefix fills exactly one SHA256 block, the state after it is computed
+// once per tag and restored for every hash
+
+var taggedStates sync.Map
+
+// TaggedHash returns the tagged hash of the concatenation of msg
+func TaggedHash(tag string, msg ...[]byte) [32]byte {
+	h := NewTaggedHash(tag)
+	for _, m := range msg {
+		h.Write(m)
+	}
+
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
+// NewTaggedHash returns a streaming tagged hash, Reset brings it back
+// to the state after the prefix
+func NewTaggedHash(tag string) hash.Hash {
+	h := &taggedHash{Hash: sha256.New(), state: taggedState(tag)}
+	h.Reset()
+	return h
+}
+
+type taggedHash struct {
+	hash.Hash
+	state []byte
+}
+
+func (h *taggedHash) Reset() {
+	if err := h.Hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(h.state); err != nil {
+		panic(err)
+	}
+}
+
+func taggedState(tag string) []byte {
+	if state, ok := taggedStates.Load(tag); ok {
+		return state.([]byte)
+	}
+
+	tagHash := sha256.Sum256([]byte(tag))
+
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	taggedStates.Store(tag, state)
+	return state
+}
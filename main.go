@@ -1,3 +1,18 @@
 package main
 
-import _ "ecc"
+import (
+	_ "address"
+	_ "amount"
+	_ "audit"
+	_ "base58"
+	_ "bech32"
+	_ "bip38"
+	_ "chaincfg"
+	_ "ecc"
+	_ "hashes"
+	_ "silentpayments"
+	_ "transaction"
+	_ "twoparty"
+	_ "vss"
+	_ "wire"
+)
@@ -0,0 +1,130 @@
+package silentpayments
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"bech32"
+	"ecc"
+	"hashes"
+)
+
+const (
+	MainNetHRP = "sp"
+	TestNetHRP = "tsp"
+	RegTestHRP = "sprt"
+
+	// silent payment addresses are longer than the 90 characters of BIP173
+	maxAddressLength = 1023
+
+	// ChangeLabel is reserved for the change outputs of the receiver
+	ChangeLabel uint32 = 0
+)
+
+var ErrInvalidAddress = errors.New("invalid silent payment address")
+
+// Address is a silent payment address, SpendKey already
+// includes the label tweak for labeled addresses
+type Address struct {
+	HRP      string
+	Version  byte
+	ScanKey  *ecc.Point
+	SpendKey *ecc.Point
+}
+
+func NewAddress(hrp string, scanKey, spendKey *ecc.Point) *Address {
+	return &Address{
+		HRP:      hrp,
+		Version:  0,
+		ScanKey:  scanKey,
+		SpendKey: spendKey,
+	}
+}
+
+func (a *Address) String() string {
+	payload := append(serP(a.ScanKey), serP(a.SpendKey)...)
+
+	data, err := bech32.ConvertBits(payload, 8, 5, true)
+	if err != nil {
+		panic(err)
+	}
+
+	addr, err := bech32.Encode(a.HRP, append([]byte{a.Version}, data...), bech32.Bech32m)
+	if err != nil {
+		panic(err)
+	}
+
+	return addr
+}
+
+// DecodeAddress parses a silent payment address of mainnet, testnet or
+// regtest. Future versions are accepted as long as they start with the
+// 66 bytes of the version 0 keys
+func DecodeAddress(addr string) (*Address, error) {
+	hrp, data, enc, err := bech32.DecodeWithLimit(addr, maxAddressLength)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+
+	if hrp != MainNetHRP && hrp != TestNetHRP && hrp != RegTestHRP {
+		return nil, fmt.Errorf("%w: unknown hrp %q", ErrInvalidAddress, hrp)
+	}
+
+	if enc != bech32.Bech32m {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddress, bech32.ErrWrongEncoding)
+	}
+
+	if len(data) == 0 {
+		return nil, ErrInvalidAddress
+	}
+
+	version := data[0]
+	if version == 31 {
+		return nil, fmt.Errorf("%w: version 31 is reserved", ErrInvalidAddress)
+	}
+
+	payload, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+
+	if (version == 0 && len(payload) != 66) || len(payload) < 66 {
+		return nil, fmt.Errorf("%w: unexpected payload length %d", ErrInvalidAddress, len(payload))
+	}
+
+	if payload[0] == 0x04 || payload[33] == 0x04 {
+		return nil, fmt.Errorf("%w: keys must be compressed", ErrInvalidAddress)
+	}
+
+	scanKey, err := ecc.FromSec(bytes.NewReader(payload[:33]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: scan key: %w", ErrInvalidAddress, err)
+	}
+
+	spendKey, err := ecc.FromSec(bytes.NewReader(payload[33:66]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: spend key: %w", ErrInvalidAddress, err)
+	}
+
+	return &Address{
+		HRP:      hrp,
+		Version:  version,
+		ScanKey:  scanKey,
+		SpendKey: spendKey,
+	}, nil
+}
+
+// labelTweak computes hash(ser256(b_scan) || ser32(m))
+func labelTweak(scanKey *ecc.PrivateKey, m uint32) *big.Int {
+	secret := make([]byte, 32)
+	scanKey.Secret().FillBytes(secret)
+
+	ser := make([]byte, 4)
+	binary.BigEndian.PutUint32(ser, m)
+
+	h := hashes.TaggedHash(labelTag, secret, ser)
+	return big.NewInt(0).SetBytes(h[:])
+}
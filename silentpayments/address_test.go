@@ -0,0 +1,83 @@
+package silentpayments_test
+
+import (
+	"math/big"
+	"silentpayments"
+	"strings"
+	"testing"
+
+	"ecc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressRoundTrip(t *testing.T) {
+	scan := ecc.NewPrivateKey(big.NewInt(1111))
+	spend := ecc.NewPrivateKey(big.NewInt(2222))
+
+	addr := silentpayments.NewAddress(silentpayments.MainNetHRP, scan.PublicKey(), spend.PublicKey())
+	encoded := addr.String()
+	require.True(t, strings.HasPrefix(encoded, "sp1q"))
+	require.Len(t, encoded, 116)
+
+	decoded, err := silentpayments.DecodeAddress(encoded)
+	require.NoError(t, err)
+	require.Equal(t, silentpayments.MainNetHRP, decoded.HRP)
+	require.Equal(t, byte(0), decoded.Version)
+	require.True(t, scan.PublicKey().EqualTo(decoded.ScanKey))
+	require.True(t, spend.PublicKey().EqualTo(decoded.SpendKey))
+
+	// uppercase addresses are valid too
+	_, err = silentpayments.DecodeAddress(strings.ToUpper(encoded))
+	require.NoError(t, err)
+
+	testnet := silentpayments.NewAddress(silentpayments.TestNetHRP, scan.PublicKey(), spend.PublicKey())
+	require.True(t, strings.HasPrefix(testnet.String(), "tsp1q"))
+}
+
+func TestDecodeInvalidAddress(t *testing.T) {
+	scan := ecc.NewPrivateKey(big.NewInt(1111))
+	spend := ecc.NewPrivateKey(big.NewInt(2222))
+	encoded := silentpayments.NewAddress(silentpayments.MainNetHRP, scan.PublicKey(), spend.PublicKey()).String()
+
+	// flip the last character of the checksum
+	last := encoded[len(encoded)-1]
+	typo := encoded[:len(encoded)-1] + string("qp"[map[bool]int{true: 1, false: 0}[last == 'q']])
+	_, err := silentpayments.DecodeAddress(typo)
+	require.ErrorIs(t, err, silentpayments.ErrInvalidAddress)
+
+	// mixed case
+	_, err = silentpayments.DecodeAddress(encoded[:10] + strings.ToUpper(encoded[10:]))
+	require.ErrorIs(t, err, silentpayments.ErrInvalidAddress)
+
+	// only the mainnet, testnet and regtest hrps are known
+	unknown := silentpayments.NewAddress("bc", scan.PublicKey(), spend.PublicKey())
+	_, err = silentpayments.DecodeAddress(unknown.String())
+	require.ErrorIs(t, err, silentpayments.ErrInvalidAddress)
+
+	regtest := silentpayments.NewAddress(silentpayments.RegTestHRP, scan.PublicKey(), spend.PublicKey())
+	decoded, err := silentpayments.DecodeAddress(regtest.String())
+	require.NoError(t, err)
+	require.Equal(t, silentpayments.RegTestHRP, decoded.HRP)
+
+	// version 31 is reserved
+	v31 := &silentpayments.Address{
+		HRP:      silentpayments.MainNetHRP,
+		Version:  31,
+		ScanKey:  scan.PublicKey(),
+		SpendKey: spend.PublicKey(),
+	}
+	_, err = silentpayments.DecodeAddress(v31.String())
+	require.ErrorIs(t, err, silentpayments.ErrInvalidAddress)
+
+	// future versions are accepted as long as they start with the keys
+	v1 := &silentpayments.Address{
+		HRP:      silentpayments.MainNetHRP,
+		Version:  1,
+		ScanKey:  scan.PublicKey(),
+		SpendKey: spend.PublicKey(),
+	}
+	decoded, err = silentpayments.DecodeAddress(v1.String())
+	require.NoError(t, err)
+	require.Equal(t, byte(1), decoded.Version)
+}
@@ -0,0 +1,163 @@
+package silentpayments
+
+import (
+	"math/big"
+
+	"ecc"
+)
+
+// Receiver holds the keys needed to scan transactions, the spend
+// private key is only required when spending the found outputs
+type Receiver struct {
+	hrp      string
+	scanKey  *ecc.PrivateKey
+	spendKey *ecc.Point
+
+	// labels indexes the registered labels by their compressed tweak point
+	labels map[string]label
+}
+
+type label struct {
+	m     uint32
+	tweak *big.Int
+}
+
+// FoundOutput is an output paying the receiver, the private key spending
+// it is the spend private key plus Tweak
+type FoundOutput struct {
+	Output [32]byte
+	Tweak  *big.Int
+
+	// Label is nil when the output pays the unlabeled address
+	Label *uint32
+}
+
+// PrivateKey returns the private key of the output
+func (f *FoundOutput) PrivateKey(spendKey *ecc.PrivateKey) *ecc.PrivateKey {
+	secret := big.NewInt(0).Add(spendKey.Secret(), f.Tweak)
+	return ecc.NewPrivateKey(secret.Mod(secret, ecc.BitcoinN))
+}
+
+func NewReceiver(hrp string, scanKey *ecc.PrivateKey, spendKey *ecc.Point) *Receiver {
+	return &Receiver{
+		hrp:      hrp,
+		scanKey:  scanKey,
+		spendKey: spendKey,
+		labels:   make(map[string]label),
+	}
+}
+
+// Address returns the unlabeled address of the receiver
+func (r *Receiver) Address() *Address {
+	return NewAddress(r.hrp, r.scanKey.PublicKey(), r.spendKey)
+}
+
+// AddLabel registers the label m so that Scan recognises its outputs
+// and returns the labeled address. ChangeLabel is meant for change
+func (r *Receiver) AddLabel(m uint32) *Address {
+	tweak := labelTweak(r.scanKey, m)
+	point := scalarBaseMul(tweak)
+
+	r.labels[string(serP(point))] = label{m: m, tweak: tweak}
+	return NewAddress(r.hrp, r.scanKey.PublicKey(), r.spendKey.Add(point))
+}
+
+// Scan looks for outputs paying the receiver among the x-only taproot
+// outputs of a transaction. inputKeys are the public keys of its eligible
+// inputs, taproot keys lifted to an even y, and outpoints every outpoint
+// it spends
+func (r *Receiver) Scan(inputKeys []*ecc.Point, outpoints []Outpoint, outputs [][32]byte) ([]*FoundOutput, error) {
+	if len(inputKeys) == 0 || len(outpoints) == 0 {
+		return nil, ErrNoInputs
+	}
+
+	sum := inputKeys[0]
+	for _, key := range inputKeys[1:] {
+		sum = sum.Add(key)
+	}
+
+	// such transaction cannot pay to silent payments
+	if sum.IsInfinity() {
+		return nil, nil
+	}
+
+	tweak := inputHash(outpoints, sum)
+	tweak.Mul(tweak, r.scanKey.Secret()).Mod(tweak, ecc.BitcoinN)
+	shared := sum.ScalarMul(tweak)
+
+	var (
+		found     []*FoundOutput
+		remaining = append([][32]byte{}, outputs...)
+	)
+
+	for k := uint32(0); ; k++ {
+		tk := sharedSecretTweak(shared, k)
+		pk := r.spendKey.Add(scalarBaseMul(tk))
+
+		match := r.match(pk, remaining)
+		if match < 0 {
+			return found, nil
+		}
+
+		found = append(found, r.found(pk, tk, remaining[match]))
+		remaining = append(remaining[:match], remaining[match+1:]...)
+	}
+}
+
+// match returns the index of the first output paying pk either directly
+// or through one of the labels, -1 if there is none
+func (r *Receiver) match(pk *ecc.Point, outputs [][32]byte) int {
+	x := xOnly(pk)
+	for i, output := range outputs {
+		if output == x {
+			return i
+		}
+	}
+
+	if len(r.labels) == 0 {
+		return -1
+	}
+
+	for i, output := range outputs {
+		if _, ok := r.labelOf(pk, output); ok {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (r *Receiver) found(pk *ecc.Point, tk *big.Int, output [32]byte) *FoundOutput {
+	f := &FoundOutput{Output: output, Tweak: tk}
+	if xOnly(pk) == output {
+		return f
+	}
+
+	l, _ := r.labelOf(pk, output)
+	m := l.m
+	f.Label = &m
+	f.Tweak = big.NewInt(0).Add(tk, l.tweak)
+	f.Tweak.Mod(f.Tweak, ecc.BitcoinN)
+	return f
+}
+
+// labelOf checks whether output - pk or -output - pk is a registered label
+func (r *Receiver) labelOf(pk *ecc.Point, output [32]byte) (label, bool) {
+	point, err := liftX(output)
+	if err != nil {
+		return label{}, false
+	}
+
+	minusPk := pk.Neg()
+	for _, candidate := range []*ecc.Point{point.Add(minusPk), point.Neg().Add(minusPk)} {
+		if candidate.IsInfinity() {
+			continue
+		}
+
+		if l, ok := r.labels[string(serP(candidate))]; ok {
+			return l, true
+		}
+	}
+
+	return label{}, false
+}
@@ -0,0 +1,68 @@
+package silentpayments
+
+import (
+	"math/big"
+
+	"ecc"
+)
+
+// SenderInput is the private key of an input eligible for silent
+// payments, keys spending taproot outputs are negated when needed so
+// that they match the x-only key committed in the output
+type SenderInput struct {
+	Key     *ecc.PrivateKey
+	Taproot bool
+}
+
+func (in SenderInput) secret() *big.Int {
+	secret := in.Key.Secret()
+	if in.Taproot && hasOddY(in.Key.PublicKey()) {
+		secret.Sub(ecc.BitcoinN, secret)
+	}
+
+	return secret
+}
+
+// CreateOutputs derives the x-only taproot output keys paying each
+// recipient, the result follows the order of the recipients. Recipients
+// sharing a scan key receive outputs with increasing k
+func CreateOutputs(inputs []SenderInput, outpoints []Outpoint, recipients []*Address) ([][32]byte, error) {
+	if len(inputs) == 0 || len(outpoints) == 0 {
+		return nil, ErrNoInputs
+	}
+
+	a := big.NewInt(0)
+	for _, in := range inputs {
+		a.Add(a, in.secret())
+	}
+
+	a.Mod(a, ecc.BitcoinN)
+	if a.Sign() == 0 {
+		return nil, ErrInputsSumZero
+	}
+
+	// input_hash * a is shared by every recipient
+	tweakedA := inputHash(outpoints, scalarBaseMul(a))
+	tweakedA.Mul(tweakedA, a).Mod(tweakedA, ecc.BitcoinN)
+
+	var (
+		outputs = make([][32]byte, len(recipients))
+		shared  = make(map[string]*ecc.Point)
+		counter = make(map[string]uint32)
+	)
+
+	for i, recipient := range recipients {
+		scan := string(serP(recipient.ScanKey))
+		if _, ok := shared[scan]; !ok {
+			shared[scan] = recipient.ScanKey.ScalarMul(tweakedA)
+		}
+
+		k := counter[scan]
+		counter[scan]++
+
+		tk := sharedSecretTweak(shared[scan], k)
+		outputs[i] = xOnly(recipient.SpendKey.Add(scalarBaseMul(tk)))
+	}
+
+	return outputs, nil
+}
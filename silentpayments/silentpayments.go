@@ -0,0 +1,90 @@
+// Package silentpayments implements BIP352: a receiver publishes a static
+// address made of a scan and a spend public key, a sender tweaks the spend
+// key with an ECDH secret derived from its inputs and the receiver finds
+// the resulting taproot outputs by scanning transactions
+package silentpayments
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"ecc"
+	"hashes"
+)
+
+var (
+	ErrNoInputs      = errors.New("at least one input and outpoint are required")
+	ErrInputsSumZero = errors.New("input keys sum to zero")
+
+	inputsTag       = "BIP0352/Inputs"
+	sharedSecretTag = "BIP0352/SharedSecret"
+	labelTag        = "BIP0352/Label"
+)
+
+// Outpoint identifies a transaction output, the hash is
+// kept in the internal byte order used on the wire
+type Outpoint struct {
+	Hash  [32]byte
+	Index uint32
+}
+
+func (o Outpoint) serialize() []byte {
+	buf := make([]byte, 36)
+	copy(buf, o.Hash[:])
+	binary.LittleEndian.PutUint32(buf[32:], o.Index)
+	return buf
+}
+
+// inputHash commits to the lexicographically smallest
+// outpoint and to the sum of the input public keys
+func inputHash(outpoints []Outpoint, sum *ecc.Point) *big.Int {
+	smallest := outpoints[0].serialize()
+	for _, o := range outpoints[1:] {
+		if ser := o.serialize(); bytes.Compare(ser, smallest) < 0 {
+			smallest = ser
+		}
+	}
+
+	h := hashes.TaggedHash(inputsTag, smallest, serP(sum))
+	return big.NewInt(0).SetBytes(h[:])
+}
+
+// sharedSecretTweak computes t_k = hash(serP(ecdh_shared_secret) || ser32(k))
+func sharedSecretTweak(shared *ecc.Point, k uint32) *big.Int {
+	ser := make([]byte, 4)
+	binary.BigEndian.PutUint32(ser, k)
+
+	h := hashes.TaggedHash(sharedSecretTag, serP(shared), ser)
+	return big.NewInt(0).SetBytes(h[:])
+}
+
+// serP returns the compressed SEC encoding of p
+func serP(p *ecc.Point) []byte {
+	sec, err := p.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	return sec
+}
+
+func xOnly(p *ecc.Point) [32]byte {
+	var x [32]byte
+	copy(x[:], p.XBytes())
+	return x
+}
+
+func hasOddY(p *ecc.Point) bool {
+	return p.YBytes()[31]&1 == 1
+}
+
+// liftX returns the point with an even y for a taproot output key
+func liftX(x [32]byte) (*ecc.Point, error) {
+	return ecc.FromSec(bytes.NewReader(append([]byte{0x02}, x[:]...)))
+}
+
+func scalarBaseMul(k *big.Int) *ecc.Point {
+	return ecc.BitcoingGenPoint.ScalarMul(k)
+}
@@ -0,0 +1,481 @@
+package silentpayments_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"silentpayments"
+	"testing"
+
+	"ecc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func outpoint(seed string, index uint32) silentpayments.Outpoint {
+	return silentpayments.Outpoint{Hash: sha256.Sum256([]byte(seed)), Index: index}
+}
+
+func serP(t *testing.T, p *ecc.Point) []byte {
+	sec, err := p.MarshalBinary()
+	require.NoError(t, err)
+	return sec
+}
+
+func xOnly(t *testing.T, p *ecc.Point) [32]byte {
+	return [32]byte(serP(t, p)[1:])
+}
+
+// evenY lifts the x-only key of a taproot input as the receiver sees it
+func evenY(t *testing.T, p *ecc.Point) *ecc.Point {
+	sec := serP(t, p)
+	sec[0] = 0x02
+
+	lifted := new(ecc.Point)
+	require.NoError(t, lifted.UnmarshalBinary(sec))
+	return lifted
+}
+
+func privateKey(t *testing.T, h string) *ecc.PrivateKey {
+	secret, ok := big.NewInt(0).SetString(h, 16)
+	require.True(t, ok)
+	return ecc.NewPrivateKey(secret)
+}
+
+// txOutpoint builds an outpoint from a txid in the usual reversed hex
+func txOutpoint(t *testing.T, txid string, vout uint32) silentpayments.Outpoint {
+	b, err := hex.DecodeString(txid)
+	require.NoError(t, err)
+	require.Len(t, b, 32)
+
+	o := silentpayments.Outpoint{Index: vout}
+	for i := range b {
+		o.Hash[31-i] = b[i]
+	}
+
+	return o
+}
+
+type bip352Input struct {
+	priv    string
+	taproot bool
+}
+
+type bip352Outpoint struct {
+	txid string
+	vout uint32
+}
+
+// cases of send_and_receive_test_vectors.json from BIP352, inputs are
+// the eligible ones with their private keys
+var bip352Vectors = []struct {
+	comment   string
+	inputs    []bip352Input
+	outpoints []bip352Outpoint
+
+	// output is empty when no output can be created
+	output string
+}{
+	{
+		comment: "Simple send: two inputs",
+		inputs: []bip352Input{
+			{priv: "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+			{priv: "93f5ed907ad5b2bdbbdcb5d9116ebc0a4e1f92f910d5260237fa45a9408aad16"},
+		},
+		outpoints: []bip352Outpoint{
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"},
+			{txid: "a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d"},
+		},
+		output: "3e9fce73d4e77a4809908e3c3a2e54ee147b9312dc5044a193d1fc85de46e3c1",
+	},
+	{
+		comment: "Simple send: two inputs, order reversed",
+		inputs: []bip352Input{
+			{priv: "93f5ed907ad5b2bdbbdcb5d9116ebc0a4e1f92f910d5260237fa45a9408aad16"},
+			{priv: "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+		},
+		outpoints: []bip352Outpoint{
+			{txid: "a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d"},
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"},
+		},
+		output: "3e9fce73d4e77a4809908e3c3a2e54ee147b9312dc5044a193d1fc85de46e3c1",
+	},
+	{
+		comment: "Simple send: two inputs from the same transaction",
+		inputs: []bip352Input{
+			{priv: "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+			{priv: "93f5ed907ad5b2bdbbdcb5d9116ebc0a4e1f92f910d5260237fa45a9408aad16"},
+		},
+		outpoints: []bip352Outpoint{
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", vout: 3},
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", vout: 7},
+		},
+		output: "79e71baa2ba3fc66396de3a04f168c7bf24d6870ec88ca877754790c1db357b6",
+	},
+	{
+		comment: "Outpoint ordering byte-lexicographically vs. vout integer",
+		inputs: []bip352Input{
+			{priv: "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+			{priv: "93f5ed907ad5b2bdbbdcb5d9116ebc0a4e1f92f910d5260237fa45a9408aad16"},
+		},
+		outpoints: []bip352Outpoint{
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", vout: 1},
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", vout: 256},
+		},
+		output: "a85ef8701394b517a4b35217c4bd37ac01ebeed4b008f8d0879f9e09ba95319c",
+	},
+	{
+		comment: "Single recipient: multiple UTXOs from the same public key",
+		inputs: []bip352Input{
+			{priv: "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+			{priv: "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+		},
+		outpoints: []bip352Outpoint{
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"},
+			{txid: "a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d"},
+		},
+		output: "548ae55c8eec1e736e8d3e520f011f1f42a56d166116ad210b3937599f87f566",
+	},
+	{
+		comment: "Single recipient: taproot only inputs with even y-values",
+		inputs: []bip352Input{
+			{priv: "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1", taproot: true},
+			{priv: "fc8716a97a48ba9a05a98ae47b5cd201a25a7fd5d8b73c203c5f7b6b6b3b6ad7", taproot: true},
+		},
+		outpoints: []bip352Outpoint{
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"},
+			{txid: "a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d"},
+		},
+		output: "de88bea8e7ffc9ce1af30d1132f910323c505185aec8eae361670421e749a1fb",
+	},
+	{
+		comment: "Single recipient: taproot only with mixed even/odd y-values",
+		inputs: []bip352Input{
+			{priv: "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1", taproot: true},
+			{priv: "1d37787c2b7116ee983e9f9c13269df29091b391c04db94239e0d2bc2182c3bf", taproot: true},
+		},
+		outpoints: []bip352Outpoint{
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"},
+			{txid: "a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d"},
+		},
+		output: "77cab7dd12b10259ee82c6ea4b509774e33e7078e7138f568092241bf26b99f1",
+	},
+	{
+		comment: "Input keys sum up to zero / point at infinity: sending fails, receiver skips tx",
+		inputs: []bip352Input{
+			{priv: "a6df6a0bb448992a301df4258e06a89fe7cf7146f59ac3bd5ff26083acb22ceb"},
+			{priv: "592095f44bb766d5cfe20bda71f9575ed2df6b9fb9addc7e5fdffe0923841456"},
+		},
+
+		// any outpoints, sending fails before they are used
+		outpoints: []bip352Outpoint{
+			{txid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"},
+			{txid: "a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d"},
+		},
+	},
+}
+
+func TestBIP352Vectors(t *testing.T) {
+	const address = "sp1qqgste7k9hx0qftg6qmwlkqtwuy6cycyavzmzj85c6qdfhjdpdjtdgqjuexzk6murw56suy3e0rd2cgqvycxttddwsvgxe2usfpxumr70xc9pkqwv"
+
+	scan := privateKey(t, "0f694e068028a717f8af6b9411f9a133dd3565258714cc226594b34db90c1f2c")
+	spend := privateKey(t, "9d6ad855ce3417ef84e836892e5a56392bfba05fa5d97ccea30e266f540e08b3")
+	receiver := silentpayments.NewReceiver(silentpayments.MainNetHRP, scan, spend.PublicKey())
+	require.Equal(t, address, receiver.Address().String())
+
+	recipient, err := silentpayments.DecodeAddress(address)
+	require.NoError(t, err)
+
+	for _, v := range bip352Vectors {
+		t.Run(v.comment, func(t *testing.T) {
+			var (
+				inputs    []silentpayments.SenderInput
+				inputKeys []*ecc.Point
+				outpoints []silentpayments.Outpoint
+			)
+
+			for _, in := range v.inputs {
+				key := privateKey(t, in.priv)
+				inputs = append(inputs, silentpayments.SenderInput{Key: key, Taproot: in.taproot})
+
+				pub := key.PublicKey()
+				if in.taproot {
+					pub = evenY(t, pub)
+				}
+
+				inputKeys = append(inputKeys, pub)
+			}
+
+			for _, o := range v.outpoints {
+				outpoints = append(outpoints, txOutpoint(t, o.txid, o.vout))
+			}
+
+			outputs, err := silentpayments.CreateOutputs(inputs, outpoints, []*silentpayments.Address{recipient})
+			if v.output == "" {
+				require.ErrorIs(t, err, silentpayments.ErrInputsSumZero)
+
+				found, err := receiver.Scan(inputKeys, outpoints, nil)
+				require.NoError(t, err)
+				require.Empty(t, found)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, outputs, 1)
+			require.Equal(t, v.output, hex.EncodeToString(outputs[0][:]))
+
+			found, err := receiver.Scan(inputKeys, outpoints, outputs)
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			require.Equal(t, outputs[0], found[0].Output)
+			require.Nil(t, found[0].Label)
+			require.Equal(t, outputs[0], xOnly(t, found[0].PrivateKey(spend).PublicKey()))
+		})
+	}
+}
+
+// receiver is one of the fixed receivers of the table tests
+type receiver struct {
+	scan, spend *ecc.PrivateKey
+}
+
+var receivers = []receiver{
+	{scan: ecc.NewPrivateKey(big.NewInt(0xdead)), spend: ecc.NewPrivateKey(big.NewInt(0xbeef))},
+	{scan: ecc.NewPrivateKey(big.NewInt(7)), spend: ecc.NewPrivateKey(big.NewInt(8))},
+}
+
+// recipient is an output paying receivers[receiver], through label when
+// it is not nil
+type recipient struct {
+	receiver int
+	label    *uint32
+}
+
+func labelPtr(m uint32) *uint32 { return &m }
+
+func TestSendAndReceive(t *testing.T) {
+	legacy := ecc.NewPrivateKey(big.NewInt(12345))
+	taproot := ecc.NewPrivateKey(big.NewInt(54321))
+
+	// one of a key and its negation has an odd y
+	negated := ecc.NewPrivateKey(big.NewInt(0).Sub(ecc.BitcoinN, big.NewInt(54321)))
+
+	cases := []struct {
+		name       string
+		inputs     []silentpayments.SenderInput
+		outpoints  []silentpayments.Outpoint
+		recipients []recipient
+	}{
+		{
+			name:       "single legacy input",
+			inputs:     []silentpayments.SenderInput{{Key: legacy}},
+			outpoints:  []silentpayments.Outpoint{outpoint("a", 0)},
+			recipients: []recipient{{receiver: 0}},
+		},
+		{
+			name:       "taproot input",
+			inputs:     []silentpayments.SenderInput{{Key: taproot, Taproot: true}},
+			outpoints:  []silentpayments.Outpoint{outpoint("a", 0)},
+			recipients: []recipient{{receiver: 0}},
+		},
+		{
+			name:       "negated taproot input",
+			inputs:     []silentpayments.SenderInput{{Key: negated, Taproot: true}},
+			outpoints:  []silentpayments.Outpoint{outpoint("a", 0)},
+			recipients: []recipient{{receiver: 0}},
+		},
+		{
+			name:       "mixed inputs with unsorted outpoints",
+			inputs:     []silentpayments.SenderInput{{Key: legacy}, {Key: taproot, Taproot: true}},
+			outpoints:  []silentpayments.Outpoint{outpoint("b", 1), outpoint("a", 0), outpoint("c", 0)},
+			recipients: []recipient{{receiver: 0}},
+		},
+		{
+			// vout is little endian, 256 serializes before 1
+			name:       "outpoints of the same transaction",
+			inputs:     []silentpayments.SenderInput{{Key: legacy}, {Key: negated, Taproot: true}},
+			outpoints:  []silentpayments.Outpoint{outpoint("a", 1), outpoint("a", 256)},
+			recipients: []recipient{{receiver: 0}},
+		},
+		{
+			name:       "several outputs to the same receiver",
+			inputs:     []silentpayments.SenderInput{{Key: legacy}},
+			outpoints:  []silentpayments.Outpoint{outpoint("a", 0)},
+			recipients: []recipient{{receiver: 0}, {receiver: 1}, {receiver: 0}},
+		},
+		{
+			name:      "labels",
+			inputs:    []silentpayments.SenderInput{{Key: legacy}, {Key: taproot, Taproot: true}},
+			outpoints: []silentpayments.Outpoint{outpoint("a", 0)},
+			recipients: []recipient{
+				{receiver: 0, label: labelPtr(42)},
+				{receiver: 0, label: labelPtr(silentpayments.ChangeLabel)},
+			},
+		},
+		{
+			name:      "labeled and unlabeled outputs share k",
+			inputs:    []silentpayments.SenderInput{{Key: legacy}},
+			outpoints: []silentpayments.Outpoint{outpoint("a", 0)},
+			recipients: []recipient{
+				{receiver: 0},
+				{receiver: 1, label: labelPtr(1)},
+				{receiver: 0, label: labelPtr(1)},
+			},
+		},
+	}
+
+	decoy := xOnly(t, ecc.NewPrivateKey(big.NewInt(999)).PublicKey())
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanners := make([]*silentpayments.Receiver, len(receivers))
+			for i, r := range receivers {
+				scanners[i] = silentpayments.NewReceiver(silentpayments.MainNetHRP, r.scan, r.spend.PublicKey())
+			}
+
+			addresses := make([]*silentpayments.Address, len(tc.recipients))
+			for i, r := range tc.recipients {
+				addr := scanners[r.receiver].Address()
+				if r.label != nil {
+					addr = scanners[r.receiver].AddLabel(*r.label)
+				}
+
+				decoded, err := silentpayments.DecodeAddress(addr.String())
+				require.NoError(t, err)
+				addresses[i] = decoded
+			}
+
+			outputs, err := silentpayments.CreateOutputs(tc.inputs, tc.outpoints, addresses)
+			require.NoError(t, err)
+			require.Len(t, outputs, len(tc.recipients))
+
+			inputKeys := make([]*ecc.Point, len(tc.inputs))
+			for i, in := range tc.inputs {
+				inputKeys[i] = in.Key.PublicKey()
+				if in.Taproot {
+					inputKeys[i] = evenY(t, inputKeys[i])
+				}
+			}
+
+			// the receiver sees the outputs in any order among others
+			scanned := [][32]byte{decoy}
+			for i := len(outputs) - 1; i >= 0; i-- {
+				scanned = append(scanned, outputs[i])
+			}
+
+			for i, scanner := range scanners {
+				found, err := scanner.Scan(inputKeys, tc.outpoints, scanned)
+				require.NoError(t, err)
+
+				want := make(map[[32]byte]*uint32)
+				for j, r := range tc.recipients {
+					if r.receiver == i {
+						want[outputs[j]] = r.label
+					}
+				}
+
+				require.Len(t, found, len(want))
+				for _, out := range found {
+					label, ok := want[out.Output]
+					require.True(t, ok)
+					require.Equal(t, label, out.Label)
+
+					// the receiver can spend the output
+					require.Equal(t, out.Output, xOnly(t, out.PrivateKey(receivers[i].spend).PublicKey()))
+				}
+			}
+		})
+	}
+}
+
+func TestScanFindsNothing(t *testing.T) {
+	scan, spend := receivers[0].scan, receivers[0].spend
+	legacy := ecc.NewPrivateKey(big.NewInt(12345))
+	outpoints := []silentpayments.Outpoint{outpoint("b", 1), outpoint("a", 0)}
+
+	labeled := silentpayments.NewReceiver(silentpayments.MainNetHRP, scan, spend.PublicKey())
+	addresses := []*silentpayments.Address{labeled.AddLabel(42)}
+	outputs, err := silentpayments.CreateOutputs([]silentpayments.SenderInput{{Key: legacy}}, outpoints, addresses)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name      string
+		receiver  *silentpayments.Receiver
+		inputKeys []*ecc.Point
+		outpoints []silentpayments.Outpoint
+	}{
+		{
+			name:      "label not registered",
+			receiver:  silentpayments.NewReceiver(silentpayments.MainNetHRP, scan, spend.PublicKey()),
+			inputKeys: []*ecc.Point{legacy.PublicKey()},
+			outpoints: outpoints,
+		},
+		{
+			name:      "swapped scan and spend keys",
+			receiver:  silentpayments.NewReceiver(silentpayments.MainNetHRP, spend, scan.PublicKey()),
+			inputKeys: []*ecc.Point{legacy.PublicKey()},
+			outpoints: outpoints,
+		},
+		{
+			name:      "smaller outpoint added",
+			receiver:  labeled,
+			inputKeys: []*ecc.Point{legacy.PublicKey()},
+			outpoints: append([]silentpayments.Outpoint{{}}, outpoints...),
+		},
+		{
+			name:      "other input key",
+			receiver:  labeled,
+			inputKeys: []*ecc.Point{spend.PublicKey()},
+			outpoints: outpoints,
+		},
+		{
+			name:      "input keys sum to infinity",
+			receiver:  labeled,
+			inputKeys: []*ecc.Point{legacy.PublicKey(), legacy.PublicKey().Neg()},
+			outpoints: outpoints,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			found, err := tc.receiver.Scan(tc.inputKeys, tc.outpoints, outputs)
+			require.NoError(t, err)
+			require.Empty(t, found)
+		})
+	}
+
+	_, err = labeled.Scan(nil, outpoints, outputs)
+	require.ErrorIs(t, err, silentpayments.ErrNoInputs)
+}
+
+func TestCreateOutputsInvalidInputs(t *testing.T) {
+	key := ecc.NewPrivateKey(big.NewInt(5))
+	minusKey := ecc.NewPrivateKey(big.NewInt(0).Sub(ecc.BitcoinN, big.NewInt(5)))
+	outpoints := []silentpayments.Outpoint{outpoint("a", 0)}
+	recipients := []*silentpayments.Address{
+		silentpayments.NewAddress(silentpayments.MainNetHRP, receivers[0].scan.PublicKey(), receivers[0].spend.PublicKey()),
+	}
+
+	cases := []struct {
+		name      string
+		inputs    []silentpayments.SenderInput
+		outpoints []silentpayments.Outpoint
+		err       error
+	}{
+		{name: "no inputs", outpoints: outpoints, err: silentpayments.ErrNoInputs},
+		{name: "no outpoints", inputs: []silentpayments.SenderInput{{Key: key}}, err: silentpayments.ErrNoInputs},
+		{
+			name:      "keys sum to zero",
+			inputs:    []silentpayments.SenderInput{{Key: key}, {Key: minusKey}},
+			outpoints: outpoints,
+			err:       silentpayments.ErrInputsSumZero,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := silentpayments.CreateOutputs(tc.inputs, tc.outpoints, recipients)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
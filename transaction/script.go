@@ -0,0 +1,119 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// The few script operations signature hashing needs: walking the
+// opcodes of a script, which may be malformed, and removing data pushes
+
+const (
+	opPushData1     = 0x4c
+	opPushData2     = 0x4d
+	opPushData4     = 0x4e
+	opCodeSeparator = 0xab
+)
+
+// nextOp returns the opcode at pc and the position of the next one. ok
+// is false at the end of the script or when a push runs past it, next
+// then is where reading stopped as in Bitcoin Core's GetScriptOp
+func nextOp(script []byte, pc int) (op byte, next int, ok bool) {
+	if pc >= len(script) {
+		return 0, pc, false
+	}
+
+	op, pc = script[pc], pc+1
+	if op > opPushData4 {
+		return op, pc, true
+	}
+
+	size := int(op)
+	switch op {
+	case opPushData1:
+		if len(script)-pc < 1 {
+			return op, pc, false
+		}
+
+		size, pc = int(script[pc]), pc+1
+	case opPushData2:
+		if len(script)-pc < 2 {
+			return op, pc, false
+		}
+
+		size, pc = int(binary.LittleEndian.Uint16(script[pc:])), pc+2
+	case opPushData4:
+		if len(script)-pc < 4 {
+			return op, pc, false
+		}
+
+		n := binary.LittleEndian.Uint32(script[pc:])
+		pc += 4
+		if uint64(n) > uint64(len(script)-pc) {
+			return op, pc, false
+		}
+
+		size = int(n)
+	}
+
+	if len(script)-pc < size {
+		return op, pc, false
+	}
+
+	return op, pc + size, true
+}
+
+// PushData returns the script pushing data with the smallest push
+// opcode for its size, the way Bitcoin Core serializes a signature
+// before looking for it in the script code
+func PushData(data []byte) []byte {
+	n := len(data)
+
+	var script []byte
+	switch {
+	case n < opPushData1:
+		script = []byte{byte(n)}
+	case n <= 0xff:
+		script = []byte{opPushData1, byte(n)}
+	case n <= 0xffff:
+		script = binary.LittleEndian.AppendUint16([]byte{opPushData2}, uint16(n))
+	default:
+		script = binary.LittleEndian.AppendUint32([]byte{opPushData4}, uint32(n))
+	}
+
+	return append(script, data...)
+}
+
+// FindAndDelete removes every occurrence of pattern starting at an
+// opcode boundary of script and returns the result with the number of
+// occurrences removed. Legacy signature checks remove the pushed
+// signature from the script code before hashing it
+func FindAndDelete(script, pattern []byte) ([]byte, int) {
+	if len(pattern) == 0 {
+		return script, 0
+	}
+
+	var result []byte
+	var found int
+	pc, kept := 0, 0
+	for {
+		result = append(result, script[kept:pc]...)
+		for bytes.HasPrefix(script[pc:], pattern) {
+			pc += len(pattern)
+			found++
+		}
+
+		kept = pc
+
+		var ok bool
+		if _, pc, ok = nextOp(script, pc); !ok {
+			break
+		}
+	}
+
+	if found == 0 {
+		return script, 0
+	}
+
+	return append(result, script[kept:]...), found
+}
@@ -0,0 +1,67 @@
+package transaction_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"transaction"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAndDelete(t *testing.T) {
+	// from Bitcoin Core's script_tests
+	vectors := []struct {
+		script   string
+		pattern  string
+		expected string
+		found    int
+	}{
+		{"0302ff03", "0302ff03", "", 1},
+		{"0302ff030302ff03", "0302ff03", "", 2},
+		// only whole opcodes match
+		{"0302ff030302ff03", "02", "0302ff030302ff03", 0},
+		{"0302ff030302ff03", "ff", "0302ff030302ff03", 0},
+		// removing the push prefix leaves a shorter push
+		{"0302ff030302ff03", "03", "02ff0302ff03", 2},
+		{"02feed5169", "feed51", "02feed5169", 0},
+		{"02feed5169", "02feed51", "69", 1},
+		{"516902feed5169", "feed51", "516902feed5169", 0},
+		{"516902feed5169", "02feed51", "516969", 1},
+		// a single pass
+		{"00005151", "0051", "0051", 1},
+		{"000051005151", "0051", "0051", 2},
+		// an invalid push at the end can be removed
+		{"0003feed", "03feed", "00", 1},
+		{"0003feed", "00", "03feed", 1},
+		{"0003feed", "", "0003feed", 0},
+	}
+
+	for _, v := range vectors {
+		script, _ := hex.DecodeString(v.script)
+		pattern, _ := hex.DecodeString(v.pattern)
+
+		result, found := transaction.FindAndDelete(script, pattern)
+		require.Equal(t, v.expected, hex.EncodeToString(result), v)
+		require.Equal(t, v.found, found, v)
+	}
+}
+
+func TestPushData(t *testing.T) {
+	require.Equal(t, "00", hex.EncodeToString(transaction.PushData(nil)))
+	require.Equal(t, "03010203", hex.EncodeToString(transaction.PushData([]byte{1, 2, 3})))
+
+	for size, prefix := range map[int]string{
+		0x4b:    "4b",
+		0x4c:    "4c4c",
+		0xff:    "4cff",
+		0x100:   "4d0001",
+		0xffff:  "4dffff",
+		0x10000: "4e00000100",
+	} {
+		data := bytes.Repeat([]byte{0xab}, size)
+		push := transaction.PushData(data)
+		require.Equal(t, prefix, hex.EncodeToString(push[:len(prefix)/2]), size)
+		require.Equal(t, data, push[len(prefix)/2:], size)
+	}
+}
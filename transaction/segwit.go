@@ -0,0 +1,86 @@
+package transaction
+
+import (
+	"io"
+
+	"hashes"
+	"wire"
+)
+
+// Segregated witness, BIP141 moves the spending data to a witness that
+// the TxID does not commit to and BIP144 serializes it after the outputs
+
+// WitnessScaleFactor is the weight of a non witness byte
+const WitnessScaleFactor = 4
+
+// HasWitness reports whether an input of t has a non empty witness
+func (t *Tx) HasWitness() bool {
+	for _, in := range t.Inputs {
+		if len(in.Witness) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WTxID returns the HASH256 of the serialization of t with its witness,
+// the TxID for transactions without one
+func (t *Tx) WTxID() wire.Hash {
+	return hashes.Hash256(t.Serialize())
+}
+
+// BaseSize is the size of the legacy serialization of t
+func (t *Tx) BaseSize() int {
+	return len(t.SerializeNoWitness())
+}
+
+// TotalSize is the size of the serialization of t with its witness
+func (t *Tx) TotalSize() int {
+	return len(t.Serialize())
+}
+
+// Weight counts non witness bytes four times and witness bytes once,
+// as defined by BIP141
+func (t *Tx) Weight() int {
+	return t.BaseSize()*(WitnessScaleFactor-1) + t.TotalSize()
+}
+
+// VSize is the weight divided by four and rounded up, the size fee
+// rates apply to
+func (t *Tx) VSize() int {
+	return (t.Weight() + WitnessScaleFactor - 1) / WitnessScaleFactor
+}
+
+func parseWitness(r io.Reader) ([][]byte, error) {
+	count, err := readCount(r, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var items [][]byte
+	for i := uint64(0); i < count; i++ {
+		item, err := wire.ReadVarBytes(r, wire.MaxSize)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func encodeWitness(w io.Writer, items [][]byte) error {
+	if err := wire.WriteCompactSize(w, uint64(len(items))); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := wire.WriteVarBytes(w, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
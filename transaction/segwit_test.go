@@ -0,0 +1,117 @@
+package transaction_test
+
+import (
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"transaction"
+	"wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+var segwitVectors = []struct {
+	name      string
+	hex       string
+	txid      string
+	wtxid     string
+	baseSize  int
+	totalSize int
+	weight    int
+	vsize     int
+}{
+	{
+		// from a past segnet, also a btcd test vector
+		"p2wpkh spend",
+		"01000000000101a53352d5135766f03076597418263da2d9c958315968fea823529467481ff9cd1300000000ffffffff010b070600000000001600149ddac6f39d51e0398e532a22c41ba189406a852302463043021f4d2381dc97f182abd8185f51753018523212f5ddc07cc4e63a8dc03658da190220608b5c4d92b86b6de7d78ef23a2fa735bcb59b914a48b0e187c5e7569a18197001210307ead084807eb76346df6977000c89392f45c76425b26181f521d7f370066a8f00000000",
+		"0f167d1385a84d1518cfee208b653fc9163b605ccf1b75347e2850b3e2eb19f3",
+		"0858eab78e77b6b033da30f46699996396cf48fcf625a783c85a51403e175e74",
+		82, 190, 436, 109,
+	},
+	{
+		// mainnet block 0000000000000000001602407ac49862a7bca9d00f7f402db20b7be2f5de59d2,
+		// a nested p2wpkh input followed by a legacy one with an empty witness
+		"mixed inputs",
+		"0200000000010224aeba5dcb17ce9e1468b2b1918fbc4d9845a3e69157c4c986c7eecb91f118a301000000171600147a94440d1d175acb113699d0774c6907eb191efdffffffffca71528b85123187c3165a82308fb5a53098589da53000a98465191a7c5acb7c030000006a47304402203952e78ce3330065f8e81a020c5910eac4a318b8bb7f4d50659cf9ebd0059931022052648b6e190e2036497536b15210871d47223c2bdaeed7729deb0f94b6fa7840012103786af4b32017ec640dba2d2a7e1fd5aa4a231a658e4cbc114d51c031576e19bcffffffff0200ae4c2d0000000017a9143fb9c437c5d478f42bc07b259692548fb5ef524a877764411f170000001976a914cebb2851a9c7cfe2582c12ecaf7f3ff4383d1dc088ac0247304402201e4ab42af63f925b8b919acb50d09cf632e3e7687cad2ab5d2362b7cc16ace7802205221b05cb592a79f15601e09633288eaf5c9098e06c3220fc7326db84bf8e9ae01210274f358b79f9209c2b2ddc9786c9c7fbb78f7a4f2493f42d0d8ea1901e7f910420000000000",
+		"6ae2101c36364bdef4a4dcc73280f7c5a5405ab1d03cd87f0ec5465b72c7131a",
+		"04540105da421e6456ec04b45bd7c8d2a7d4724e4c8a563c1be1398ea66a411e",
+		287, 397, 1258, 315,
+	},
+}
+
+func TestParseSegwit(t *testing.T) {
+	for _, v := range segwitVectors {
+		tx, err := transaction.ParseHex(v.hex)
+		require.NoError(t, err, v.name)
+		require.True(t, tx.HasWitness(), v.name)
+		require.Equal(t, v.hex, tx.Hex(), v.name)
+		require.Equal(t, v.txid, tx.TxID().String(), v.name)
+		require.Equal(t, v.wtxid, tx.WTxID().String(), v.name)
+		require.Equal(t, v.baseSize, tx.BaseSize(), v.name)
+		require.Equal(t, v.totalSize, tx.TotalSize(), v.name)
+		require.Equal(t, v.weight, tx.Weight(), v.name)
+		require.Equal(t, v.vsize, tx.VSize(), v.name)
+
+		// without its witness the transaction is its legacy serialization
+		legacy, err := transaction.ParseBytes(tx.SerializeNoWitness())
+		require.NoError(t, err, v.name)
+		require.False(t, legacy.HasWitness(), v.name)
+		require.Equal(t, v.txid, legacy.TxID().String(), v.name)
+		require.Equal(t, v.txid, legacy.WTxID().String(), v.name)
+	}
+
+	mixed, err := transaction.ParseHex(segwitVectors[1].hex)
+	require.NoError(t, err)
+	require.Len(t, mixed.Inputs[0].Witness, 2)
+	require.Empty(t, mixed.Inputs[1].Witness)
+
+	for _, v := range legacyVectors {
+		tx, err := transaction.ParseHex(v.hex)
+		require.NoError(t, err)
+		require.Equal(t, tx.TxID(), tx.WTxID())
+		require.Equal(t, tx.BaseSize(), tx.TotalSize())
+		require.Equal(t, 4*tx.TotalSize(), tx.Weight())
+		require.Equal(t, tx.TotalSize(), tx.VSize())
+	}
+}
+
+func TestParseWitnessErrors(t *testing.T) {
+	segwit := segwitVectors[0].hex
+	// version | marker | flags | input | output, then the witness
+	inputOutput := segwit[12:strings.Index(segwit, "02463043")]
+
+	// an empty transaction is read as such, the zero flags being its
+	// output count
+	empty, err := transaction.ParseHex("01000000" + "00" + "00" + "00000000")
+	require.NoError(t, err)
+	require.Empty(t, empty.Inputs)
+	require.Empty(t, empty.Outputs)
+	require.Equal(t, "01000000000000000000", empty.Hex())
+
+	invalid := []struct {
+		name string
+		hex  string
+		err  error
+	}{
+		{"empty witnesses", "01000000" + "0001" + inputOutput + "00" + "00000000", transaction.ErrSuperfluousWitness},
+		{"no inputs", "01000000" + "0001" + "00" + "00" + "00000000", transaction.ErrSuperfluousWitness},
+		{"unknown flags", "01000000" + "0002" + inputOutput + "00000000", transaction.ErrUnknownFlags},
+		{"witness and unknown flags", "01000000" + "0003" + inputOutput + "0100" + "00000000", transaction.ErrUnknownFlags},
+		{"non-canonical stack size", "01000000" + "0001" + inputOutput + "fd0100" + "00" + "00000000", wire.ErrNonCanonical},
+		{"truncated witness", segwit[:len(segwit)-80], io.ErrUnexpectedEOF},
+		{"missing flags", "01000000" + "00", io.ErrUnexpectedEOF},
+	}
+
+	for _, v := range invalid {
+		_, err := transaction.ParseHex(v.hex)
+		require.ErrorIs(t, err, transaction.ErrInvalidTx, v.name)
+		require.ErrorIs(t, err, v.err, v.name)
+	}
+
+	raw, _ := hex.DecodeString(segwit)
+	for i := range raw {
+		_, err := transaction.ParseBytes(raw[:i])
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF, i)
+	}
+}
@@ -0,0 +1,194 @@
+package transaction
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+
+	"hashes"
+	"wire"
+)
+
+// Legacy signature hashing, the message signed by inputs spending
+// outputs that are not segwit. The transaction is serialized with the
+// script code of the signed input in place of its scriptSig, other
+// scriptSigs emptied and inputs and outputs trimmed by the sighash
+// type, followed by the type as a 4 bytes integer
+
+// SigHashType selects the parts of a transaction a signature commits to,
+// it is appended to signatures as a single byte
+type SigHashType uint32
+
+const (
+	SigHashAll          SigHashType = 0x01
+	SigHashNone         SigHashType = 0x02
+	SigHashSingle       SigHashType = 0x03
+	SigHashAnyOneCanPay SigHashType = 0x80
+
+	// the base type is read from the low bits, other bits but
+	// ANYONECANPAY are ignored and still hashed
+	sigHashBaseMask = 0x1f
+)
+
+// sigHashOne is what Bitcoin Core returns for an input without a
+// matching output under SIGHASH_SINGLE, or an input out of range,
+// instead of failing. Signatures of this value are valid for any
+// transaction
+var sigHashOne = wire.Hash{0x01}
+
+func (h SigHashType) String() string {
+	var base string
+	switch h & sigHashBaseMask {
+	case SigHashAll:
+		base = "ALL"
+	case SigHashNone:
+		base = "NONE"
+	case SigHashSingle:
+		base = "SINGLE"
+	default:
+		return fmt.Sprintf("SigHashType(%#x)", uint32(h))
+	}
+
+	if h&SigHashAnyOneCanPay != 0 {
+		return base + "|ANYONECANPAY"
+	}
+
+	return base
+}
+
+// LegacySigHash returns the signature hash of input idx of t, the
+// scriptCode is the script of the spent output from its last executed
+// OP_CODESEPARATOR. OP_CODESEPARATORs left in it are not hashed, the
+// signature itself must already be removed with FindAndDelete. It
+// reproduces the SIGHASH_SINGLE bug, see sigHashOne
+func (t *Tx) LegacySigHash(idx int, scriptCode []byte, hashType SigHashType) wire.Hash {
+	if idx < 0 || idx >= len(t.Inputs) {
+		return sigHashOne
+	}
+
+	base := hashType & sigHashBaseMask
+	if base == SigHashSingle && idx >= len(t.Outputs) {
+		return sigHashOne
+	}
+
+	var buf bytes.Buffer
+	if err := t.encodeLegacySigHash(&buf, idx, scriptCode, hashType); err != nil {
+		panic(err)
+	}
+
+	return hashes.Hash256(buf.Bytes())
+}
+
+func (t *Tx) encodeLegacySigHash(w io.Writer, idx int, scriptCode []byte, hashType SigHashType) error {
+	base := hashType & sigHashBaseMask
+	anyoneCanPay := hashType&SigHashAnyOneCanPay != 0
+
+	if err := wire.WriteInt32(w, t.Version); err != nil {
+		return err
+	}
+
+	inputs := t.Inputs
+	if anyoneCanPay {
+		inputs = t.Inputs[idx : idx+1]
+	}
+
+	if err := wire.WriteCompactSize(w, uint64(len(inputs))); err != nil {
+		return err
+	}
+
+	for i, in := range inputs {
+		signed := anyoneCanPay || i == idx
+
+		if err := wire.WriteHash(w, in.PreviousOutPoint.Hash); err != nil {
+			return err
+		}
+
+		if err := wire.WriteUint32(w, in.PreviousOutPoint.Index); err != nil {
+			return err
+		}
+
+		script := []byte{0x00}
+		if signed {
+			script = serializeScriptCode(scriptCode)
+		}
+
+		if _, err := w.Write(script); err != nil {
+			return err
+		}
+
+		// other inputs may be replaced when their outputs are not signed
+		sequence := in.Sequence
+		if !signed && (base == SigHashNone || base == SigHashSingle) {
+			sequence = 0
+		}
+
+		if err := wire.WriteUint32(w, sequence); err != nil {
+			return err
+		}
+	}
+
+	outputs := t.Outputs
+	switch base {
+	case SigHashNone:
+		outputs = nil
+	case SigHashSingle:
+		outputs = t.Outputs[:idx+1]
+	}
+
+	if err := wire.WriteCompactSize(w, uint64(len(outputs))); err != nil {
+		return err
+	}
+
+	for i, out := range outputs {
+		// outputs before the signed one are blanked under SIGHASH_SINGLE
+		if base == SigHashSingle && i != idx {
+			out = &TxOut{Value: -1}
+		}
+
+		if err := out.encode(w); err != nil {
+			return err
+		}
+	}
+
+	if err := wire.WriteUint32(w, t.LockTime); err != nil {
+		return err
+	}
+
+	return wire.WriteUint32(w, uint32(hashType))
+}
+
+// serializeScriptCode writes scriptCode as var bytes without its
+// OP_CODESEPARATORs. Like Bitcoin Core the length counts every byte but
+// the separators while the bytes stop where a malformed push starts
+func serializeScriptCode(scriptCode []byte) []byte {
+	separators := 0
+	for pc, ok := 0, true; ok; {
+		var op byte
+		if op, pc, ok = nextOp(scriptCode, pc); ok && op == opCodeSeparator {
+			separators++
+		}
+	}
+
+	out := wire.AppendCompactSize(nil, uint64(len(scriptCode)-separators))
+
+	begin, pc := 0, 0
+	for {
+		op, next, ok := nextOp(scriptCode, pc)
+		if !ok {
+			return append(out, scriptCode[begin:next]...)
+		}
+
+		if op == opCodeSeparator {
+			out = append(out, scriptCode[begin:next-1]...)
+			begin = next
+		}
+
+		pc = next
+	}
+}
+
+// Z returns the signature hash as the integer ECDSA signs
+func Z(sigHash wire.Hash) *big.Int {
+	return big.NewInt(0).SetBytes(sigHash[:])
+}
@@ -0,0 +1,106 @@
+package transaction_test
+
+import (
+	"bytes"
+	"ecc"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+	"transaction"
+
+	"hashes"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestLegacySigHashVectors runs Bitcoin Core's sighash.json, random
+// transactions and scripts full of OP_CODESEPARATORs hashed with random
+// types
+func TestLegacySigHashVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/sighash.json")
+	require.NoError(t, err)
+
+	var vectors [][]any
+	require.NoError(t, json.Unmarshal(data, &vectors))
+
+	// the first entry describes the format
+	vectors = vectors[1:]
+	require.Len(t, vectors, 500)
+
+	for i, v := range vectors {
+		tx, err := transaction.ParseHex(v[0].(string))
+		require.NoError(t, err, i)
+
+		script, err := hex.DecodeString(v[1].(string))
+		require.NoError(t, err, i)
+
+		idx := int(v[2].(float64))
+		hashType := transaction.SigHashType(uint32(int32(v[3].(float64))))
+
+		sigHash := tx.LegacySigHash(idx, script, hashType)
+		require.Equal(t, v[4].(string), sigHash.String(), "vector %d %s", i, hashType)
+	}
+}
+
+func TestLegacySigHashSingleBug(t *testing.T) {
+	tx, err := transaction.ParseHex(segwitVectors[1].hex)
+	require.NoError(t, err)
+
+	// one output less than inputs, the second input signs the value one
+	tx.Outputs = tx.Outputs[:1]
+	one := "0000000000000000000000000000000000000000000000000000000000000001"
+
+	require.Equal(t, one, tx.LegacySigHash(1, nil, transaction.SigHashSingle).String())
+	require.Equal(t, one, tx.LegacySigHash(1, nil, transaction.SigHashSingle|transaction.SigHashAnyOneCanPay).String())
+	require.Equal(t, one, tx.LegacySigHash(2, nil, transaction.SigHashAll).String())
+	require.NotEqual(t, one, tx.LegacySigHash(0, nil, transaction.SigHashSingle).String())
+	require.NotEqual(t, one, tx.LegacySigHash(1, nil, transaction.SigHashAll).String())
+}
+
+// TestLegacySigHashMainnet verifies the signature of the legacy P2PKH
+// input of a mainnet transaction
+func TestLegacySigHashMainnet(t *testing.T) {
+	tx, err := transaction.ParseHex(segwitVectors[1].hex)
+	require.NoError(t, err)
+
+	// scriptSig: <signature with its type> <public key>
+	scriptSig := tx.Inputs[1].ScriptSig
+	sig := scriptSig[1 : 1+scriptSig[0]]
+	pubKey := scriptSig[2+scriptSig[0]:]
+	require.Len(t, pubKey, 33)
+
+	hashType := transaction.SigHashType(sig[len(sig)-1])
+	require.Equal(t, transaction.SigHashAll, hashType)
+
+	pubKeyHash := hashes.Hash160(pubKey)
+	scriptPubKey := append(append([]byte{0x76, 0xa9, 0x14}, pubKeyHash[:]...), 0x88, 0xac)
+
+	// the signature is removed from the script code, there is none here
+	scriptCode, found := transaction.FindAndDelete(scriptPubKey, transaction.PushData(sig))
+	require.Zero(t, found)
+
+	z := transaction.Z(tx.LegacySigHash(1, scriptCode, hashType))
+
+	point, err := ecc.FromSec(bytes.NewReader(pubKey))
+	require.NoError(t, err)
+	signature, err := ecc.ParseDER(sig[:len(sig)-1])
+	require.NoError(t, err)
+	require.True(t, point.Verify(ecc.NewFieldElement(ecc.BitcoinN, z), signature))
+
+	// any other type or input hashes to another message
+	for _, other := range []transaction.SigHashType{transaction.SigHashNone, transaction.SigHashSingle, transaction.SigHashAll | transaction.SigHashAnyOneCanPay} {
+		z := transaction.Z(tx.LegacySigHash(1, scriptCode, other))
+		require.False(t, point.Verify(ecc.NewFieldElement(ecc.BitcoinN, z), signature), other)
+	}
+
+	z = transaction.Z(tx.LegacySigHash(0, scriptCode, hashType))
+	require.False(t, point.Verify(ecc.NewFieldElement(ecc.BitcoinN, z), signature))
+}
+
+func TestSigHashTypeString(t *testing.T) {
+	require.Equal(t, "ALL", transaction.SigHashAll.String())
+	require.Equal(t, "NONE|ANYONECANPAY", (transaction.SigHashNone | transaction.SigHashAnyOneCanPay).String())
+	require.Equal(t, "SINGLE", (transaction.SigHashSingle | 0x40).String())
+	require.Equal(t, "SigHashType(0x0)", transaction.SigHashType(0).String())
+}
@@ -0,0 +1,387 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"amount"
+	"hashes"
+	"wire"
+)
+
+// Transactions in the legacy serialization
+//
+//	version | inputs | outputs | locktime
+//
+// where inputs and outputs are prefixed by their CompactSize count, or
+// in the BIP144 serialization when an input has a witness
+//
+//	version | 0x00 | flags | inputs | outputs | witnesses | locktime
+//
+// The TxID is the HASH256 of the legacy serialization and the WTxID the
+// one of the full serialization, both displayed reversed
+
+const (
+	// smallest serialized input and output, bounding forged counts
+	minTxInSize  = wire.HashSize + 4 + 1 + 4
+	minTxOutSize = 8 + 1
+
+	// coinbase inputs spend this index of the zero hash
+	coinbaseIndex = 0xffffffff
+
+	// the empty input vector a legacy parser sees, followed by flags
+	witnessMarker = 0x00
+	witnessFlag   = 0x01
+)
+
+var (
+	ErrInvalidTx          = errors.New("invalid transaction")
+	ErrSuperfluousWitness = errors.New("superfluous witness record")
+	ErrUnknownFlags       = errors.New("unknown transaction optional data")
+	ErrInputIndex         = errors.New("input index out of range")
+)
+
+// OutPoint references an output of a previous transaction
+type OutPoint struct {
+	Hash  wire.Hash
+	Index uint32
+}
+
+// TxIn spends a previous output, the witness holds the stack items of
+// segwit spends and is not part of the TxID
+type TxIn struct {
+	PreviousOutPoint OutPoint
+	ScriptSig        []byte
+	Sequence         uint32
+	Witness          [][]byte
+}
+
+type TxOut struct {
+	Value        amount.Amount
+	ScriptPubKey []byte
+}
+
+type Tx struct {
+	Version  int32
+	Inputs   []*TxIn
+	Outputs  []*TxOut
+	LockTime uint32
+}
+
+func (o OutPoint) String() string {
+	return fmt.Sprintf("%s:%d", o.Hash, o.Index)
+}
+
+// Parse reads a transaction from r, io.EOF is returned as is when r is
+// empty so a stream of transactions ends cleanly
+func Parse(r io.Reader) (*Tx, error) {
+	t := new(Tx)
+
+	var err error
+	if t.Version, err = wire.ReadInt32(r); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+
+		return nil, invalid("version", err)
+	}
+
+	// as Bitcoin Core does, an empty input vector is the witness marker
+	// and the byte after it the flags, which are the empty output vector
+	// of a transaction without inputs when zero
+	if t.Inputs, err = parseTxIns(r); err != nil {
+		return nil, err
+	}
+
+	var flags byte
+	if len(t.Inputs) == 0 {
+		if flags, err = wire.ReadUint8(r); err != nil {
+			return nil, invalid("flags", err)
+		}
+
+		if flags != 0 {
+			if t.Inputs, err = parseTxIns(r); err != nil {
+				return nil, err
+			}
+
+			if t.Outputs, err = parseTxOuts(r); err != nil {
+				return nil, err
+			}
+		}
+	} else if t.Outputs, err = parseTxOuts(r); err != nil {
+		return nil, err
+	}
+
+	if flags&witnessFlag != 0 {
+		flags ^= witnessFlag
+		for i, in := range t.Inputs {
+			if in.Witness, err = parseWitness(r); err != nil {
+				return nil, invalid(fmt.Sprintf("witness %d", i), err)
+			}
+		}
+
+		if !t.HasWitness() {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTx, ErrSuperfluousWitness)
+		}
+	}
+
+	if flags != 0 {
+		return nil, fmt.Errorf("%w: %w: %#02x", ErrInvalidTx, ErrUnknownFlags, flags)
+	}
+
+	if t.LockTime, err = wire.ReadUint32(r); err != nil {
+		return nil, invalid("locktime", err)
+	}
+
+	return t, nil
+}
+
+// ParseBytes parses a transaction spanning all of b
+func ParseBytes(b []byte) (*Tx, error) {
+	r := bytes.NewReader(b)
+	t, err := Parse(r)
+	if err != nil {
+		return nil, invalid("transaction", err)
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidTx, r.Len())
+	}
+
+	return t, nil
+}
+
+// ParseHex parses the hex encoding of a transaction, as returned by
+// getrawtransaction
+func ParseHex(s string) (*Tx, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTx, err)
+	}
+
+	return ParseBytes(b)
+}
+
+// Encode writes the serialization of t to w, the BIP144 one when t has
+// a witness
+func (t *Tx) Encode(w io.Writer) error {
+	return t.encode(w, t.HasWitness())
+}
+
+// EncodeNoWitness writes the legacy serialization of t to w
+func (t *Tx) EncodeNoWitness(w io.Writer) error {
+	return t.encode(w, false)
+}
+
+func (t *Tx) encode(w io.Writer, witness bool) error {
+	if err := wire.WriteInt32(w, t.Version); err != nil {
+		return err
+	}
+
+	if witness {
+		if _, err := w.Write([]byte{witnessMarker, witnessFlag}); err != nil {
+			return err
+		}
+	}
+
+	if err := wire.WriteCompactSize(w, uint64(len(t.Inputs))); err != nil {
+		return err
+	}
+
+	for _, in := range t.Inputs {
+		if err := in.encode(w); err != nil {
+			return err
+		}
+	}
+
+	if err := wire.WriteCompactSize(w, uint64(len(t.Outputs))); err != nil {
+		return err
+	}
+
+	for _, out := range t.Outputs {
+		if err := out.encode(w); err != nil {
+			return err
+		}
+	}
+
+	if witness {
+		for _, in := range t.Inputs {
+			if err := encodeWitness(w, in.Witness); err != nil {
+				return err
+			}
+		}
+	}
+
+	return wire.WriteUint32(w, t.LockTime)
+}
+
+// Serialize returns the serialization of t, with its witness if any
+func (t *Tx) Serialize() []byte {
+	var buf bytes.Buffer
+	if err := t.Encode(&buf); err != nil {
+		panic(err)
+	}
+
+	return buf.Bytes()
+}
+
+// SerializeNoWitness returns the legacy serialization of t
+func (t *Tx) SerializeNoWitness() []byte {
+	var buf bytes.Buffer
+	if err := t.EncodeNoWitness(&buf); err != nil {
+		panic(err)
+	}
+
+	return buf.Bytes()
+}
+
+func (t *Tx) Hex() string {
+	return hex.EncodeToString(t.Serialize())
+}
+
+// TxID returns the HASH256 of the legacy serialization of t
+func (t *Tx) TxID() wire.Hash {
+	return hashes.Hash256(t.SerializeNoWitness())
+}
+
+// IsCoinbase reports whether t is the first transaction of a block,
+// with a single input spending no previous output
+func (t *Tx) IsCoinbase() bool {
+	if len(t.Inputs) != 1 {
+		return false
+	}
+
+	prev := t.Inputs[0].PreviousOutPoint
+	return prev.Index == coinbaseIndex && prev.Hash == wire.Hash{}
+}
+
+func parseTxIns(r io.Reader) ([]*TxIn, error) {
+	count, err := readCount(r, minTxInSize)
+	if err != nil {
+		return nil, invalid("input count", err)
+	}
+
+	var ins []*TxIn
+	for i := uint64(0); i < count; i++ {
+		in, err := parseTxIn(r)
+		if err != nil {
+			return nil, invalid(fmt.Sprintf("input %d", i), err)
+		}
+
+		ins = append(ins, in)
+	}
+
+	return ins, nil
+}
+
+func parseTxOuts(r io.Reader) ([]*TxOut, error) {
+	count, err := readCount(r, minTxOutSize)
+	if err != nil {
+		return nil, invalid("output count", err)
+	}
+
+	var outs []*TxOut
+	for i := uint64(0); i < count; i++ {
+		out, err := parseTxOut(r)
+		if err != nil {
+			return nil, invalid(fmt.Sprintf("output %d", i), err)
+		}
+
+		outs = append(outs, out)
+	}
+
+	return outs, nil
+}
+
+func parseTxIn(r io.Reader) (*TxIn, error) {
+	in := new(TxIn)
+
+	var err error
+	if in.PreviousOutPoint.Hash, err = wire.ReadHash(r); err != nil {
+		return nil, err
+	}
+
+	if in.PreviousOutPoint.Index, err = wire.ReadUint32(r); err != nil {
+		return nil, err
+	}
+
+	if in.ScriptSig, err = wire.ReadVarBytes(r, wire.MaxSize); err != nil {
+		return nil, err
+	}
+
+	if in.Sequence, err = wire.ReadUint32(r); err != nil {
+		return nil, err
+	}
+
+	return in, nil
+}
+
+func (in *TxIn) encode(w io.Writer) error {
+	if err := wire.WriteHash(w, in.PreviousOutPoint.Hash); err != nil {
+		return err
+	}
+
+	if err := wire.WriteUint32(w, in.PreviousOutPoint.Index); err != nil {
+		return err
+	}
+
+	if err := wire.WriteVarBytes(w, in.ScriptSig); err != nil {
+		return err
+	}
+
+	return wire.WriteUint32(w, in.Sequence)
+}
+
+func parseTxOut(r io.Reader) (*TxOut, error) {
+	value, err := wire.ReadInt64(r)
+	if err != nil {
+		return nil, err
+	}
+
+	script, err := wire.ReadVarBytes(r, wire.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TxOut{Value: amount.Amount(value), ScriptPubKey: script}, nil
+}
+
+func (out *TxOut) encode(w io.Writer) error {
+	if err := wire.WriteInt64(w, int64(out.Value)); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, out.ScriptPubKey)
+}
+
+// readCount reads the number of items of at least itemSize bytes that
+// follow, counts that cannot fit MaxSize are rejected
+func readCount(r io.Reader, itemSize uint64) (uint64, error) {
+	n, err := wire.ReadCompactSize(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > wire.MaxSize/itemSize {
+		return 0, fmt.Errorf("%w: %d items", wire.ErrTooLarge, n)
+	}
+
+	return n, nil
+}
+
+// invalid wraps a parsing error of field, reading past the end of the
+// data is always a truncation
+func invalid(field string, err error) error {
+	if errors.Is(err, ErrInvalidTx) {
+		return err
+	}
+
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return fmt.Errorf("%w: %s: %w", ErrInvalidTx, field, err)
+}
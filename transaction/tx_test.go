@@ -0,0 +1,147 @@
+package transaction_test
+
+import (
+	"amount"
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+	"transaction"
+	"wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+var legacyVectors = []struct {
+	name string
+	hex  string
+	txid string
+}{
+	{
+		"genesis coinbase",
+		"01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000",
+		"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+	},
+	{
+		"block 113875 coinbase",
+		"01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff070431dc001b0162ffffffff0100f2052a01000000434104d64bdfd09eb1c5fe295abdeb1dca4281be988e2da0b6c1c6a59dc226c28624e18175e851c96b973d81b01cc31f047834bc06d6d6edf620d184241a6aed8b63a6ac00000000",
+		"f051e59b5e2503ac626d03aaeac8ab7be2d72ba4b7e97119c5852d70d52dcb86",
+	},
+}
+
+func TestParseLegacy(t *testing.T) {
+	for _, v := range legacyVectors {
+		tx, err := transaction.ParseHex(v.hex)
+		require.NoError(t, err, v.name)
+		require.Equal(t, v.hex, tx.Hex(), v.name)
+		require.Equal(t, v.txid, tx.TxID().String(), v.name)
+		require.True(t, tx.IsCoinbase(), v.name)
+
+		require.Equal(t, int32(1), tx.Version)
+		require.Len(t, tx.Inputs, 1)
+		require.Len(t, tx.Outputs, 1)
+		require.Equal(t, 50*amount.SatoshiPerBitcoin, tx.Outputs[0].Value)
+		require.Equal(t, uint32(0xffffffff), tx.Inputs[0].Sequence)
+	}
+
+	genesis, err := transaction.ParseHex(legacyVectors[0].hex)
+	require.NoError(t, err)
+	require.Contains(t, string(genesis.Inputs[0].ScriptSig), "Chancellor on brink of second bailout for banks")
+}
+
+func TestSerializeLegacy(t *testing.T) {
+	prev, err := wire.ParseHash(legacyVectors[1].txid)
+	require.NoError(t, err)
+
+	tx := &transaction.Tx{
+		Version: 2,
+		Inputs: []*transaction.TxIn{
+			{PreviousOutPoint: transaction.OutPoint{Hash: prev, Index: 0}, ScriptSig: []byte{0x51}, Sequence: 0xfffffffd},
+			{PreviousOutPoint: transaction.OutPoint{Hash: prev, Index: 300}, Sequence: 0},
+		},
+		Outputs: []*transaction.TxOut{
+			{Value: 1_000, ScriptPubKey: bytes.Repeat([]byte{0x6a}, 0xfd)},
+			{Value: 0},
+		},
+		LockTime: 840_000,
+	}
+
+	serialized := tx.Serialize()
+	require.Equal(t, "02000000"+"02", hex.EncodeToString(serialized[:5]))
+	require.Equal(t, "40d10c00", hex.EncodeToString(serialized[len(serialized)-4:]))
+	require.False(t, tx.IsCoinbase())
+	require.Equal(t, legacyVectors[1].txid+":300", tx.Inputs[1].PreviousOutPoint.String())
+
+	parsed, err := transaction.ParseBytes(serialized)
+	require.NoError(t, err)
+	require.Equal(t, tx.TxID(), parsed.TxID())
+	require.Equal(t, serialized, parsed.Serialize())
+	require.Equal(t, uint32(300), parsed.Inputs[1].PreviousOutPoint.Index)
+	require.Len(t, parsed.Outputs[0].ScriptPubKey, 0xfd)
+}
+
+func TestParseStream(t *testing.T) {
+	var stream []byte
+	for _, v := range legacyVectors {
+		b, _ := hex.DecodeString(v.hex)
+		stream = append(stream, b...)
+	}
+
+	r := bytes.NewReader(stream)
+	for _, v := range legacyVectors {
+		tx, err := transaction.Parse(r)
+		require.NoError(t, err)
+		require.Equal(t, v.txid, tx.TxID().String())
+	}
+
+	_, err := transaction.Parse(r)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestParseErrors(t *testing.T) {
+	raw, _ := hex.DecodeString(legacyVectors[1].hex)
+
+	// every truncation fails
+	for i := range raw {
+		_, err := transaction.ParseBytes(raw[:i])
+		require.ErrorIs(t, err, transaction.ErrInvalidTx, i)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF, i)
+	}
+
+	_, err := transaction.ParseBytes(append(raw, 0x00))
+	require.ErrorIs(t, err, transaction.ErrInvalidTx)
+
+	_, err = transaction.ParseHex(legacyVectors[1].hex[1:])
+	require.ErrorIs(t, err, transaction.ErrInvalidTx)
+
+	// non-canonical input count
+	_, err = transaction.ParseHex("01000000fd0100" + legacyVectors[1].hex[10:])
+	require.ErrorIs(t, err, wire.ErrNonCanonical)
+
+	// a forged count is rejected before reading the inputs
+	_, err = transaction.ParseHex("01000000feffffff00")
+	require.ErrorIs(t, err, wire.ErrTooLarge)
+}
+
+func FuzzParse(f *testing.F) {
+	for _, v := range legacyVectors {
+		b, _ := hex.DecodeString(v.hex)
+		f.Add(b)
+	}
+
+	for _, v := range segwitVectors {
+		b, _ := hex.DecodeString(v.hex)
+		f.Add(b)
+	}
+
+	// a parsed transaction serializes to the bytes it was read from
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+		tx, err := transaction.Parse(r)
+		if err != nil {
+			return
+		}
+
+		require.Equal(t, data[:len(data)-r.Len()], tx.Serialize())
+	})
+}
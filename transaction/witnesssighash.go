@@ -0,0 +1,188 @@
+package transaction
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"amount"
+	"hashes"
+	"wire"
+)
+
+// Segwit v0 signature hashing as specified by BIP143, the message signed
+// by P2WPKH and P2WSH inputs. It commits to the value of the spent output
+// and replaces the inputs and outputs by three hashes shared by every
+// input of a transaction, so hashing all of them is linear in its size:
+//
+//	version | hashPrevouts | hashSequence | outpoint | scriptCode |
+//	value | sequence | hashOutputs | locktime | sighash type
+//
+// The scriptCode is written as is, without removing OP_CODESEPARATORs or
+// signatures
+
+// SigHashCache holds the hashes of the inputs and outputs of a
+// transaction BIP143 signature hashes are built from. The transaction
+// must not be modified while the cache is in use
+type SigHashCache struct {
+	tx *Tx
+
+	hashPrevouts wire.Hash
+	hashSequence wire.Hash
+	hashOutputs  wire.Hash
+}
+
+// NewSigHashCache hashes the outpoints, sequences and outputs of t once
+// for all its inputs
+func NewSigHashCache(t *Tx) *SigHashCache {
+	var prevouts, sequences, outputs bytes.Buffer
+	if err := t.encodeSigHashParts(&prevouts, &sequences, &outputs); err != nil {
+		panic(err)
+	}
+
+	return &SigHashCache{
+		tx:           t,
+		hashPrevouts: hashes.Hash256(prevouts.Bytes()),
+		hashSequence: hashes.Hash256(sequences.Bytes()),
+		hashOutputs:  hashes.Hash256(outputs.Bytes()),
+	}
+}
+
+func (t *Tx) encodeSigHashParts(prevouts, sequences, outputs io.Writer) error {
+	for _, in := range t.Inputs {
+		if err := wire.WriteHash(prevouts, in.PreviousOutPoint.Hash); err != nil {
+			return err
+		}
+
+		if err := wire.WriteUint32(prevouts, in.PreviousOutPoint.Index); err != nil {
+			return err
+		}
+
+		if err := wire.WriteUint32(sequences, in.Sequence); err != nil {
+			return err
+		}
+	}
+
+	for _, out := range t.Outputs {
+		if err := out.encode(outputs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HashPrevouts returns the HASH256 of the outpoints of every input
+func (c *SigHashCache) HashPrevouts() wire.Hash { return c.hashPrevouts }
+
+// HashSequence returns the HASH256 of the sequences of every input
+func (c *SigHashCache) HashSequence() wire.Hash { return c.hashSequence }
+
+// HashOutputs returns the HASH256 of every output
+func (c *SigHashCache) HashOutputs() wire.Hash { return c.hashOutputs }
+
+// WitnessV0SigHash returns the BIP143 signature hash of input idx of t
+// spending an output of the given value. Use a SigHashCache to hash
+// several inputs of the same transaction. ErrInputIndex is returned when
+// t has no input idx
+func (t *Tx) WitnessV0SigHash(idx int, scriptCode []byte, value amount.Amount, hashType SigHashType) (wire.Hash, error) {
+	return NewSigHashCache(t).WitnessV0SigHash(idx, scriptCode, value, hashType)
+}
+
+// WitnessV0SigHash returns the BIP143 signature hash of input idx of the
+// cached transaction. The scriptCode of a P2WPKH input is the P2PKH
+// script of its key hash, see WitnessPubKeyHashScriptCode, the one of a
+// P2WSH input is its witness script from the last executed
+// OP_CODESEPARATOR. Unlike legacy hashing SIGHASH_SINGLE without a
+// matching output is not special, the outputs are simply not signed.
+// An index out of range, as found in untrusted PSBTs, is an ErrInputIndex
+func (c *SigHashCache) WitnessV0SigHash(idx int, scriptCode []byte, value amount.Amount, hashType SigHashType) (wire.Hash, error) {
+	if idx < 0 || idx >= len(c.tx.Inputs) {
+		return wire.Hash{}, fmt.Errorf("%w: %d of %d inputs", ErrInputIndex, idx, len(c.tx.Inputs))
+	}
+
+	var buf bytes.Buffer
+	if err := c.encodeWitnessV0SigHash(&buf, idx, scriptCode, value, hashType); err != nil {
+		panic(err)
+	}
+
+	return hashes.Hash256(buf.Bytes()), nil
+}
+
+func (c *SigHashCache) encodeWitnessV0SigHash(w io.Writer, idx int, scriptCode []byte, value amount.Amount, hashType SigHashType) error {
+	base := hashType & sigHashBaseMask
+	anyoneCanPay := hashType&SigHashAnyOneCanPay != 0
+	in := c.tx.Inputs[idx]
+
+	// the hashes of what is not signed are left zero
+	var hashPrevouts, hashSequence, hashOutputs wire.Hash
+	if !anyoneCanPay {
+		hashPrevouts = c.hashPrevouts
+	}
+
+	if !anyoneCanPay && base != SigHashSingle && base != SigHashNone {
+		hashSequence = c.hashSequence
+	}
+
+	switch {
+	case base != SigHashSingle && base != SigHashNone:
+		hashOutputs = c.hashOutputs
+	case base == SigHashSingle && idx < len(c.tx.Outputs):
+		var out bytes.Buffer
+		if err := c.tx.Outputs[idx].encode(&out); err != nil {
+			return err
+		}
+
+		hashOutputs = hashes.Hash256(out.Bytes())
+	}
+
+	if err := wire.WriteInt32(w, c.tx.Version); err != nil {
+		return err
+	}
+
+	if err := wire.WriteHash(w, hashPrevouts); err != nil {
+		return err
+	}
+
+	if err := wire.WriteHash(w, hashSequence); err != nil {
+		return err
+	}
+
+	if err := wire.WriteHash(w, in.PreviousOutPoint.Hash); err != nil {
+		return err
+	}
+
+	if err := wire.WriteUint32(w, in.PreviousOutPoint.Index); err != nil {
+		return err
+	}
+
+	if err := wire.WriteVarBytes(w, scriptCode); err != nil {
+		return err
+	}
+
+	if err := wire.WriteInt64(w, int64(value)); err != nil {
+		return err
+	}
+
+	if err := wire.WriteUint32(w, in.Sequence); err != nil {
+		return err
+	}
+
+	if err := wire.WriteHash(w, hashOutputs); err != nil {
+		return err
+	}
+
+	if err := wire.WriteUint32(w, c.tx.LockTime); err != nil {
+		return err
+	}
+
+	return wire.WriteUint32(w, uint32(hashType))
+}
+
+// WitnessPubKeyHashScriptCode returns the scriptCode P2WPKH inputs are
+// signed with, the P2PKH script of their key hash:
+// OP_DUP OP_HASH160 <pubKeyHash> OP_EQUALVERIFY OP_CHECKSIG
+func WitnessPubKeyHashScriptCode(pubKeyHash [hashes.Hash160Size]byte) []byte {
+	script := append([]byte{0x76, 0xa9}, PushData(pubKeyHash[:])...)
+	return append(script, 0x88, 0xac)
+}
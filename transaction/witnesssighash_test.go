@@ -0,0 +1,318 @@
+package transaction_test
+
+import (
+	"amount"
+	"bytes"
+	"ecc"
+	"encoding/hex"
+	"hashes"
+	"math/big"
+	"testing"
+	"transaction"
+	"wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+// BIP143 examples and witness tests of Bitcoin Core's tx_valid.json with
+// the values of their spent outputs
+
+// bip143Vectors are the P2WPKH examples of BIP143 with their unsigned
+// transaction, intermediate hashes and the signature of the example
+// private key, all hashes in signing byte order
+var bip143Vectors = []struct {
+	name         string
+	hex          string
+	input        int
+	value        amount.Amount
+	scriptCode   string
+	hashPrevouts string
+	hashSequence string
+	hashOutputs  string
+	sigHash      string
+	pubKey       string
+	sig          string
+}{
+	{
+		name:         "native P2WPKH",
+		hex:          "0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000",
+		input:        1,
+		value:        600000000,
+		scriptCode:   "76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac",
+		hashPrevouts: "96b827c8483d4e9b96712b6713a7b68d6e8003a781feba36c31143470b4efd37",
+		hashSequence: "52b0a642eea2fb7ae638c36f6252b6750293dbe574a806984b8e4d8548339a3b",
+		hashOutputs:  "863ef3e1a92afbfdb97f31ad0fc7683ee943e9abcf2501590ff8f6551f47e5e5",
+		sigHash:      "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670",
+		pubKey:       "025476c2e83188368da1ff3e292e7acafcdb3566bb0ad253f62fc70f07aeee6357",
+		sig:          "304402203609e17b84f6a7d30c80bfa610b5b4542f32a8a0d5447a12fb1366d7f01cc44a0220573a954c4518331561406f90300e8f3358f51928d43c212a8caed02de67eebee01",
+	},
+	{
+		name:         "P2SH-P2WPKH",
+		hex:          "0100000001db6b1b20aa0fd7b23880be2ecbd4a98130974cf4748fb66092ac4d3ceb1a54770100000000feffffff02b8b4eb0b000000001976a914a457b684d7f0d539a46a45bbc043f35b59d0d96388ac0008af2f000000001976a914fd270b1ee6abcaea97fea7ad0402e8bd8ad6d77c88ac92040000",
+		input:        0,
+		value:        1000000000,
+		scriptCode:   "76a91479091972186c449eb1ded22b78e40d009bdf008988ac",
+		hashPrevouts: "b0287b4a252ac05af83d2dcef00ba313af78a3e9c329afa216eb3aa2a7b4613a",
+		hashSequence: "18606b350cd8bf565266bc352f0caddcf01e8fa789dd8a15386327cf8cabe198",
+		hashOutputs:  "de984f44532e2173ca0d64314fcefe6d30da6f8cf27bafa706da61df8a226c83",
+		sigHash:      "64f3b0f4dd2bb3aa1ce8566d220cc74dda9df97d8490cc81d89d735c92e59fb6",
+		pubKey:       "03ad1d8e89212f0b92c74d23bb710c00662ad1470198ac48c43f7d6f93a2a26873",
+		sig:          "3044022047ac8e878352d3ebbde1c94ce3a10d057c24175747116f8288e5d794d12d482f0220217f36a485cae903c713331d877c1f64677e3622ad4010726870540656fe9dcb01",
+	},
+}
+
+func TestWitnessV0SigHashBIP143(t *testing.T) {
+	for _, v := range bip143Vectors {
+		tx, err := transaction.ParseHex(v.hex)
+		require.NoError(t, err, v.name)
+
+		cache := transaction.NewSigHashCache(tx)
+		requireHash(t, v.hashPrevouts, cache.HashPrevouts())
+		requireHash(t, v.hashSequence, cache.HashSequence())
+		requireHash(t, v.hashOutputs, cache.HashOutputs())
+
+		pubKey, _ := hex.DecodeString(v.pubKey)
+		scriptCode := transaction.WitnessPubKeyHashScriptCode(hashes.Hash160(pubKey))
+		require.Equal(t, v.scriptCode, hex.EncodeToString(scriptCode), v.name)
+
+		sigHash := witnessSigHash(t, cache, v.input, scriptCode, v.value, transaction.SigHashAll)
+		requireHash(t, v.sigHash, sigHash)
+
+		sig, _ := hex.DecodeString(v.sig)
+		requireValidSig(t, pubKey, sig, sigHash)
+	}
+}
+
+// witnessSig locates a signature of a P2WSH input: the witness item
+// holding it, the offset of its public key push in the witness script
+// and where the scriptCode starts, after the last executed
+// OP_CODESEPARATOR
+type witnessSig struct {
+	input      int
+	item       int
+	key        int
+	scriptCode int
+}
+
+var p2wshVectors = []struct {
+	name   string
+	hex    string
+	values []amount.Amount
+	sigs   []witnessSig
+}{
+	{
+		name:   "OP_CODESEPARATOR and SIGHASH_SINGLE out of range",
+		hex:    "01000000000102fe3dc9208094f3ffd12645477b3dc56f60ec4fa8e6f5d67c565d1c6b9216b36e000000004847304402200af4e47c9b9629dbecc21f73af989bdaa911f7e6f6c2e9394588a3aa68f81e9902204f3fcf6ade7e5abb1295b6774c8e0abd94ae62217367096bc02ee5e435b67da201ffffffff0815cf020f013ed6cf91d29f4202e8a58726b1ac6c79da47c23d1bee0a6925f80000000000ffffffff0100f2052a010000001976a914a30741f8145e5acadf23f751864167f32e0963f788ac000347304402200de66acf4527789bfda55fc5459e214fa6083f936b430a762c629656216805ac0220396f550692cd347171cbc1ef1f51e15282e837bb2b30860dc77c8f78bc8501e503473044022027dc95ad6b740fe5129e7e62a75dd00f291a2aeb1200b84b09d9e3789406b6c002201a9ecd315dd6a0e632ab20bbb98948bc0c6fb204f2c286963bb48517a7058e27034721026dccc749adc2a9d0d89497ac511f760f45c47dc5ed9cf352a58ac706453880aeadab210255a9626aebf5e29c0e6538428ba0d1dcf6ca98ffdf086aa8ced5e0d0215ea465ac00000000",
+		values: []amount.Amount{156250000, 4900000000},
+		sigs: []witnessSig{
+			// <key1> CHECKSIGVERIFY CODESEPARATOR <key2> CHECKSIG
+			{input: 1, item: 0, key: 36, scriptCode: 36},
+			{input: 1, item: 1, key: 0, scriptCode: 0},
+		},
+	},
+	{
+		name:   "unexecuted OP_CODESEPARATOR and SINGLE|ANYONECANPAY",
+		hex:    "01000000000102e9b542c5176808107ff1df906f46bb1f2583b16112b95ee5380665ba7fcfc0010000000000ffffffff80e68831516392fcd100d186b3c2c7b95c80b53c77e77c35ba03a66b429a2a1b0000000000ffffffff0280969800000000001976a914de4b231626ef508c9a74a8517e6783c0546d6b2888ac80969800000000001976a9146648a8cd4531e1ec47f35916de8e259237294d1e88ac02483045022100f6a10b8604e6dc910194b79ccfc93e1bc0ec7c03453caaa8987f7d6c3413566002206216229ede9b4d6ec2d325be245c5b508ff0339bf1794078e20bfe0babc7ffe683270063ab68210392972e2eb617b2388771abe27235fd5ac44af8e61693261550447a4c3e39da98ac024730440220032521802a76ad7bf74d0e2c218b72cf0cbc867066e2e53db905ba37f130397e02207709e2188ed7f08f4c952d9d13986da504502b8c3be59617e043552f506c46ff83275163ab68210392972e2eb617b2388771abe27235fd5ac44af8e61693261550447a4c3e39da98ac00000000",
+		values: []amount.Amount{16777215, 16777215},
+		sigs: []witnessSig{
+			// 0 IF CODESEPARATOR ENDIF <key> CHECKSIG
+			{input: 0, item: 0, key: 4, scriptCode: 0},
+			// 1 IF CODESEPARATOR ENDIF <key> CHECKSIG
+			{input: 1, item: 0, key: 4, scriptCode: 3},
+		},
+	},
+	{
+		name:   "input-output pairs swapped",
+		hex:    "0100000000010280e68831516392fcd100d186b3c2c7b95c80b53c77e77c35ba03a66b429a2a1b0000000000ffffffffe9b542c5176808107ff1df906f46bb1f2583b16112b95ee5380665ba7fcfc0010000000000ffffffff0280969800000000001976a9146648a8cd4531e1ec47f35916de8e259237294d1e88ac80969800000000001976a914de4b231626ef508c9a74a8517e6783c0546d6b2888ac024730440220032521802a76ad7bf74d0e2c218b72cf0cbc867066e2e53db905ba37f130397e02207709e2188ed7f08f4c952d9d13986da504502b8c3be59617e043552f506c46ff83275163ab68210392972e2eb617b2388771abe27235fd5ac44af8e61693261550447a4c3e39da98ac02483045022100f6a10b8604e6dc910194b79ccfc93e1bc0ec7c03453caaa8987f7d6c3413566002206216229ede9b4d6ec2d325be245c5b508ff0339bf1794078e20bfe0babc7ffe683270063ab68210392972e2eb617b2388771abe27235fd5ac44af8e61693261550447a4c3e39da98ac00000000",
+		values: []amount.Amount{16777215, 16777215},
+		sigs: []witnessSig{
+			{input: 0, item: 0, key: 4, scriptCode: 3},
+			{input: 1, item: 0, key: 4, scriptCode: 0},
+		},
+	},
+	{
+		name:   "P2SH-P2WSH 6-of-6 multisig with 6 sighash types",
+		hex:    "0100000000010136641869ca081e70f394c6948e8af409e18b619df2ed74aa106c1ca29787b96e0100000023220020a16b5755f7f6f96dbd65f5f0d6ab9418b89af4b1f14a1bb8a09062c35f0dcb54ffffffff0200e9a435000000001976a914389ffce9cd9ae88dcc0631e88a821ffdbe9bfe2688acc0832f05000000001976a9147480a33f950689af511e6e84c138dbbd3c3ee41588ac080047304402206ac44d672dac41f9b00e28f4df20c52eeb087207e8d758d76d92c6fab3b73e2b0220367750dbbe19290069cba53d096f44530e4f98acaa594810388cf7409a1870ce01473044022068c7946a43232757cbdf9176f009a928e1cd9a1a8c212f15c1e11ac9f2925d9002205b75f937ff2f9f3c1246e547e54f62e027f64eefa2695578cc6432cdabce271502473044022059ebf56d98010a932cf8ecfec54c48e6139ed6adb0728c09cbe1e4fa0915302e022007cd986c8fa870ff5d2b3a89139c9fe7e499259875357e20fcbb15571c76795403483045022100fbefd94bd0a488d50b79102b5dad4ab6ced30c4069f1eaa69a4b5a763414067e02203156c6a5c9cf88f91265f5a942e96213afae16d83321c8b31bb342142a14d16381483045022100a5263ea0553ba89221984bd7f0b13613db16e7a70c549a86de0cc0444141a407022005c360ef0ae5a5d4f9f2f87a56c1546cc8268cab08c73501d6b3be2e1e1a8a08824730440220525406a1482936d5a21888260dc165497a90a15669636d8edca6b9fe490d309c022032af0c646a34a44d1f4576bf6a4a74b67940f8faa84c7df9abe12a01a11e2b4783cf56210307b8ae49ac90a048e9b53357a2354b3334e9c8bee813ecb98e99a7e07e8c3ba32103b28f0c28bfab54554ae8c658ac5c3e0ce6e79ad336331f78c428dd43eea8449b21034b8113d703413d57761b8b9781957b8c0ac1dfe69f492580ca4195f50376ba4a21033400f6afecb833092a9a21cfdf1ed1376e58c5d1f47de74683123987e967a8f42103a6d48b1131e94ba04d9737d61acdaa1322008af9602b3b14862c07a1789aac162102d8b661b0b3302ee2f162b09e07a55ad5dfbe673a9f01d9f0c19617681024306b56ae00000000",
+		values: []amount.Amount{987654321},
+		sigs: []witnessSig{
+			{input: 0, item: 1, key: 1},
+			{input: 0, item: 2, key: 35},
+			{input: 0, item: 3, key: 69},
+			{input: 0, item: 4, key: 103},
+			{input: 0, item: 5, key: 137},
+			{input: 0, item: 6, key: 171},
+		},
+	},
+}
+
+func TestWitnessV0SigHashP2WSH(t *testing.T) {
+	for _, v := range p2wshVectors {
+		tx, err := transaction.ParseHex(v.hex)
+		require.NoError(t, err, v.name)
+
+		cache := transaction.NewSigHashCache(tx)
+		for _, s := range v.sigs {
+			witness := tx.Inputs[s.input].Witness
+			script := witness[len(witness)-1]
+			sig := witness[s.item]
+			hashType := transaction.SigHashType(sig[len(sig)-1])
+
+			sigHash := witnessSigHash(t, cache, s.input, script[s.scriptCode:], v.values[s.input], hashType)
+			require.Equal(t, witnessSigHash(t, tx, s.input, script[s.scriptCode:], v.values[s.input], hashType), sigHash)
+
+			requireValidSig(t, script[s.key+1:s.key+34], sig, sigHash)
+		}
+	}
+}
+
+func TestWitnessV0SigHashP2WPKH(t *testing.T) {
+	// two P2WPKH inputs signed with SINGLE|ANYONECANPAY, once in each order
+	vectors := []struct {
+		hex    string
+		values []amount.Amount
+	}{
+		{"0100000000010200010000000000000000000000000000000000000000000000000000000000000000000000ffffffff00010000000000000000000000000000000000000000000000000000000000000100000000ffffffff02e8030000000000000151e90300000000000001510247304402206d59682663faab5e4cb733c562e22cdae59294895929ec38d7c016621ff90da0022063ef0af5f970afe8a45ea836e3509b8847ed39463253106ac17d19c437d3d56b832103596d3451025c19dbbdeb932d6bf8bfb4ad499b95b6f88db8899efac102e5fc710248304502210085001a820bfcbc9f9de0298af714493f8a37b3b354bfd21a7097c3e009f2018c022050a8b4dbc8155d4d04da2f5cdd575dcf8dd0108de8bec759bd897ea01ecb3af7832103596d3451025c19dbbdeb932d6bf8bfb4ad499b95b6f88db8899efac102e5fc7100000000", []amount.Amount{1000, 1001}},
+		{"0100000000010200010000000000000000000000000000000000000000000000000000000000000100000000ffffffff00010000000000000000000000000000000000000000000000000000000000000000000000ffffffff02e9030000000000000151e80300000000000001510248304502210085001a820bfcbc9f9de0298af714493f8a37b3b354bfd21a7097c3e009f2018c022050a8b4dbc8155d4d04da2f5cdd575dcf8dd0108de8bec759bd897ea01ecb3af7832103596d3451025c19dbbdeb932d6bf8bfb4ad499b95b6f88db8899efac102e5fc710247304402206d59682663faab5e4cb733c562e22cdae59294895929ec38d7c016621ff90da0022063ef0af5f970afe8a45ea836e3509b8847ed39463253106ac17d19c437d3d56b832103596d3451025c19dbbdeb932d6bf8bfb4ad499b95b6f88db8899efac102e5fc7100000000", []amount.Amount{1001, 1000}},
+	}
+
+	for _, v := range vectors {
+		tx, err := transaction.ParseHex(v.hex)
+		require.NoError(t, err)
+
+		cache := transaction.NewSigHashCache(tx)
+		for i, in := range tx.Inputs {
+			sig, pubKey := in.Witness[0], in.Witness[1]
+			hashType := transaction.SigHashType(sig[len(sig)-1])
+			require.Equal(t, transaction.SigHashSingle|transaction.SigHashAnyOneCanPay, hashType)
+
+			scriptCode := transaction.WitnessPubKeyHashScriptCode(hashes.Hash160(pubKey))
+			requireValidSig(t, pubKey, sig, witnessSigHash(t, cache, i, scriptCode, v.values[i], hashType))
+
+			// the value is signed
+			sigHash := witnessSigHash(t, cache, i, scriptCode, v.values[i]+1, hashType)
+			require.NotEqual(t, witnessSigHash(t, cache, i, scriptCode, v.values[i], hashType), sigHash)
+		}
+	}
+}
+
+// TestWitnessV0SigHashNoFindAndDelete checks signatures in witness
+// scripts are hashed, FindAndDelete only applies to legacy scripts
+func TestWitnessV0SigHashNoFindAndDelete(t *testing.T) {
+	vectors := []struct {
+		hex      string
+		expected string
+	}{
+		{
+			hex:      "0100000000010169c12106097dc2e0526493ef67f21269fe888ef05c7a3a5dacab38e1ac8387f14c1d000000ffffffff01010000000000000000034830450220487fb382c4974de3f7d834c1b617fe15860828c7f96454490edd6d891556dcc9022100baf95feb48f845d5bfc9882eb6aeefa1bc3790e39f59eaa46ff7f15ae626c53e012102a9781d66b61fb5a7ef00ac5ad5bc6ffc78be7b44a566e3c87870e1079368df4c4aad4830450220487fb382c4974de3f7d834c1b617fe15860828c7f96454490edd6d891556dcc9022100baf95feb48f845d5bfc9882eb6aeefa1bc3790e39f59eaa46ff7f15ae626c53e0100000000",
+			expected: "71c9cd9b2869b9c70b01b1f0360c148f42dee72297db312638df136f43311f23",
+		},
+		{
+			hex:      "010000000001019275cb8d4a485ce95741c013f7c0d28722160008021bb469a11982d47a6628964c1d000000ffffffff0101000000000000000007004830450220487fb382c4974de3f7d834c1b617fe15860828c7f96454490edd6d891556dcc9022100baf95feb48f845d5bfc9882eb6aeefa1bc3790e39f59eaa46ff7f15ae626c53e0148304502205286f726690b2e9b0207f0345711e63fa7012045b9eb0f19c2458ce1db90cf43022100e89f17f86abc5b149eba4115d4f128bcf45d77fb3ecdd34f594091340c0395960101022102966f109c54e85d3aee8321301136cedeb9fc710fdef58a9de8a73942f8e567c021034ffc99dd9a79dd3cb31e2ab3e0b09e0e67db41ac068c625cd1f491576016c84e9552af4830450220487fb382c4974de3f7d834c1b617fe15860828c7f96454490edd6d891556dcc9022100baf95feb48f845d5bfc9882eb6aeefa1bc3790e39f59eaa46ff7f15ae626c53e0148304502205286f726690b2e9b0207f0345711e63fa7012045b9eb0f19c2458ce1db90cf43022100e89f17f86abc5b149eba4115d4f128bcf45d77fb3ecdd34f594091340c039596017500000000",
+			expected: "c1628a1e7c67f14ca0c27c06e4fdeec2e6d1a73c7a91d7c046ff83e835aebb72",
+		},
+	}
+
+	for _, v := range vectors {
+		tx, err := transaction.ParseHex(v.hex)
+		require.NoError(t, err)
+
+		witness := tx.Inputs[0].Witness
+		script := witness[len(witness)-1]
+		sigHash := witnessSigHash(t, tx, 0, script, 200000, transaction.SigHashAll)
+		requireHash(t, v.expected, sigHash)
+	}
+}
+
+func TestWitnessV0SigHashTypes(t *testing.T) {
+	tx, err := transaction.ParseHex(p2wshVectors[0].hex)
+	require.NoError(t, err)
+
+	cache := transaction.NewSigHashCache(tx)
+	script := []byte{0x51}
+	seen := map[wire.Hash]transaction.SigHashType{}
+	for _, base := range []transaction.SigHashType{transaction.SigHashAll, transaction.SigHashNone, transaction.SigHashSingle} {
+		for _, hashType := range []transaction.SigHashType{base, base | transaction.SigHashAnyOneCanPay} {
+			for idx := range tx.Inputs {
+				sigHash := witnessSigHash(t, cache, idx, script, 1, hashType)
+				require.NotContains(t, seen, sigHash, hashType)
+				seen[sigHash] = hashType
+			}
+		}
+	}
+
+	// no SIGHASH_SINGLE bug, the second input has no output to sign
+	one := "0000000000000000000000000000000000000000000000000000000000000001"
+	require.NotEqual(t, one, witnessSigHash(t, cache, 1, script, 1, transaction.SigHashSingle).String())
+
+	for _, idx := range []int{-1, 2} {
+		_, err = cache.WitnessV0SigHash(idx, script, 1, transaction.SigHashAll)
+		require.ErrorIs(t, err, transaction.ErrInputIndex)
+		_, err = tx.WitnessV0SigHash(idx, script, 1, transaction.SigHashAll)
+		require.ErrorIs(t, err, transaction.ErrInputIndex)
+	}
+}
+
+// TestSigHashCacheSign signs every input of a transaction with one cache
+func TestSigHashCacheSign(t *testing.T) {
+	tx, err := transaction.ParseHex(p2wshVectors[3].hex)
+	require.NoError(t, err)
+
+	tx.Inputs = append(tx.Inputs, tx.Inputs[0], tx.Inputs[0], tx.Inputs[0])
+	for i, in := range tx.Inputs {
+		in := *in
+		in.PreviousOutPoint.Index = uint32(i)
+		tx.Inputs[i] = &in
+	}
+
+	key := ecc.NewPrivateKey(big.NewInt(0xb1b0143))
+	pubKey, err := key.PublicKey().MarshalBinary()
+	require.NoError(t, err)
+	scriptCode := transaction.WitnessPubKeyHashScriptCode(hashes.Hash160(pubKey))
+
+	cache := transaction.NewSigHashCache(tx)
+	for i := range tx.Inputs {
+		value := amount.Amount(i+1) * amount.SatoshiPerBitcoin
+		z := transaction.Z(witnessSigHash(t, cache, i, scriptCode, value, transaction.SigHashAll))
+		sig := append(key.Sign(z).Der(), byte(transaction.SigHashAll))
+
+		requireValidSig(t, pubKey, sig, witnessSigHash(t, tx, i, scriptCode, value, transaction.SigHashAll))
+	}
+}
+
+func TestWitnessPubKeyHashScriptCode(t *testing.T) {
+	var pubKeyHash [hashes.Hash160Size]byte
+	copy(pubKeyHash[:], bytes.Repeat([]byte{0xab}, hashes.Hash160Size))
+
+	scriptCode := transaction.WitnessPubKeyHashScriptCode(pubKeyHash)
+	require.Equal(t, "76a914abababababababababababababababababababab88ac", hex.EncodeToString(scriptCode))
+}
+
+// witnessSigHash returns the BIP143 signature hash of a valid input
+func witnessSigHash(t *testing.T, h interface {
+	WitnessV0SigHash(int, []byte, amount.Amount, transaction.SigHashType) (wire.Hash, error)
+}, idx int, scriptCode []byte, value amount.Amount, hashType transaction.SigHashType) wire.Hash {
+	t.Helper()
+
+	sigHash, err := h.WitnessV0SigHash(idx, scriptCode, value, hashType)
+	require.NoError(t, err)
+	return sigHash
+}
+
+// requireHash compares a hash in signing byte order, not reversed
+func requireHash(t *testing.T, expected string, h wire.Hash) {
+	t.Helper()
+	require.Equal(t, expected, hex.EncodeToString(h[:]))
+}
+
+// requireValidSig verifies a DER signature followed by its sighash type
+func requireValidSig(t *testing.T, pubKey, sig []byte, sigHash wire.Hash) {
+	t.Helper()
+
+	point, err := ecc.FromSec(bytes.NewReader(pubKey))
+	require.NoError(t, err)
+	signature, err := ecc.ParseDER(sig[:len(sig)-1])
+	require.NoError(t, err)
+
+	z := transaction.Z(sigHash)
+	require.True(t, point.Verify(ecc.NewFieldElement(ecc.BitcoinN, z), signature))
+}
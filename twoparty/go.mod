@@ -0,0 +1,27 @@
+module twoparty
+
+go 1.23.1
+
+replace base58 => ../base58
+
+replace chaincfg => ../chaincfg
+
+replace ecc => ../ecc
+
+replace hashes => ../hashes
+
+replace wire => ../wire
+
+require ecc v0.0.0-00010101000000-000000000000
+
+require wire v0.0.0-00010101000000-000000000000 // indirect
+
+require (
+	base58 v0.0.0-00010101000000-000000000000 // indirect
+	chaincfg v0.0.0-00010101000000-000000000000 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/testify v1.10.0
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	hashes v0.0.0-00010101000000-000000000000 // indirect
+)
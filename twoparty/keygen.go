@@ -0,0 +1,324 @@
+package twoparty
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"ecc"
+)
+
+// Party1Key is the share of the first party, which holds the
+// paillier private key and finishes every signature
+type Party1Key struct {
+	PublicKey *ecc.Point
+
+	secret   *big.Int
+	paillier *PaillierPrivateKey
+}
+
+// Party2Key is the share of the second party, which holds
+// the encryption of the first party share
+type Party2Key struct {
+	PublicKey *ecc.Point
+
+	secret   *big.Int
+	paillier *PaillierPublicKey
+	ckey     *big.Int
+}
+
+type keyGenMsg1 struct {
+	Commitment []byte `json:"commitment"`
+}
+
+type keyGenMsg2 struct {
+	Q2    string `json:"q2"`
+	Proof []byte `json:"proof"`
+}
+
+type keyGenMsg3 struct {
+	Q1    string `json:"q1"`
+	Proof []byte `json:"proof"`
+	Nonce []byte `json:"nonce"`
+
+	N        *big.Int         `json:"n"`
+	KeyProof []*big.Int       `json:"key_proof"`
+	CKey     *big.Int         `json:"ckey"`
+	Range    *rangeCommitment `json:"range"`
+}
+
+// keyGenMsg4 challenges the range proof and starts the proof that
+// ckey encrypts the discrete log of Q1: c' = Enc(a * x1 + b)
+type keyGenMsg4 struct {
+	RangeChallenge []byte   `json:"range_challenge"`
+	CPrime         *big.Int `json:"c_prime"`
+	ABCommitment   []byte   `json:"ab_commitment"`
+}
+
+type keyGenMsg5 struct {
+	Range          []*rangeResponse `json:"range"`
+	QHatCommitment []byte           `json:"q_hat_commitment"`
+}
+
+type keyGenMsg6 struct {
+	A     *big.Int `json:"a"`
+	B     *big.Int `json:"b"`
+	Nonce []byte   `json:"nonce"`
+}
+
+type keyGenMsg7 struct {
+	QHat  string `json:"q_hat"`
+	Nonce []byte `json:"nonce"`
+}
+
+// KeyGenParty1 runs the key generation as the first party, generating
+// a paillier key of the given size
+func KeyGenParty1(t Transport, paillierBits int) (*Party1Key, error) {
+	q := ecc.BitcoinN
+
+	// x1 is drawn from [1, q / 3) for the range proof
+	l := big.NewInt(0).Div(q, big.NewInt(3))
+	x1, err := randomScalar(l)
+	if err != nil {
+		return nil, err
+	}
+
+	q1 := ecc.BitcoingGenPoint.ScalarMul(x1)
+	proof1, err := proveDLog(x1, proofMessage("keygen/1", nil))
+	if err != nil {
+		return nil, err
+	}
+
+	commitment, nonce, err := commit([]byte(encodePoint(q1)), proof1)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := send(t, &keyGenMsg1{Commitment: commitment}); err != nil {
+		return nil, err
+	}
+
+	var msg2 keyGenMsg2
+	if err := receive(t, &msg2); err != nil {
+		return nil, err
+	}
+
+	q2, err := decodePoint(msg2.Q2)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ecc.VerifyDLogProof(q2, msg2.Proof, nil, proofMessage("keygen/2", nil)) {
+		return nil, fmt.Errorf("%w: party 2 key share", ErrInvalidProof)
+	}
+
+	paillier, err := GeneratePaillierKey(paillierBits)
+	if err != nil {
+		return nil, err
+	}
+
+	ckey, r, err := paillier.Encrypt(x1)
+	if err != nil {
+		return nil, err
+	}
+
+	prover, rangeCommitment, err := newRangeProver(paillier, x1, r, l)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := send(t, &keyGenMsg3{
+		Q1:       encodePoint(q1),
+		Proof:    proof1,
+		Nonce:    nonce,
+		N:        paillier.N,
+		KeyProof: paillier.ProveKey(),
+		CKey:     ckey,
+		Range:    rangeCommitment,
+	}); err != nil {
+		return nil, err
+	}
+
+	var msg4 keyGenMsg4
+	if err := receive(t, &msg4); err != nil {
+		return nil, err
+	}
+
+	if len(msg4.RangeChallenge) != (rangeProofRounds+7)/8 {
+		return nil, fmt.Errorf("%w: range proof challenge", ErrInvalidProof)
+	}
+
+	alpha, err := paillier.Decrypt(msg4.CPrime)
+	if err != nil {
+		return nil, err
+	}
+
+	qHat := ecc.BitcoingGenPoint.ScalarMul(alpha)
+	qHatCommitment, qHatNonce, err := commit([]byte(encodePoint(qHat)))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := send(t, &keyGenMsg5{
+		Range:          prover.respond(msg4.RangeChallenge),
+		QHatCommitment: qHatCommitment,
+	}); err != nil {
+		return nil, err
+	}
+
+	var msg6 keyGenMsg6
+	if err := receive(t, &msg6); err != nil {
+		return nil, err
+	}
+
+	if msg6.A == nil || msg6.B == nil || msg6.A.Sign() < 0 || msg6.B.Sign() < 0 {
+		return nil, fmt.Errorf("%w: invalid a or b", ErrInvalidProof)
+	}
+
+	if err := verifyCommitment(msg4.ABCommitment, msg6.Nonce, msg6.A.Bytes(), msg6.B.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// revealing alpha * G is only safe when c' was honestly built
+	expected := big.NewInt(0).Mul(msg6.A, x1)
+	if expected.Add(expected, msg6.B).Cmp(alpha) != 0 {
+		return nil, fmt.Errorf("%w: c' does not encrypt a * x1 + b", ErrInvalidProof)
+	}
+
+	if err := send(t, &keyGenMsg7{QHat: encodePoint(qHat), Nonce: qHatNonce}); err != nil {
+		return nil, err
+	}
+
+	return &Party1Key{
+		PublicKey: q2.ScalarMul(x1),
+		secret:    x1,
+		paillier:  paillier,
+	}, nil
+}
+
+// KeyGenParty2 runs the key generation as the second party, verifying
+// the paillier key and the encryption of the first party share
+func KeyGenParty2(t Transport) (*Party2Key, error) {
+	q := ecc.BitcoinN
+
+	var msg1 keyGenMsg1
+	if err := receive(t, &msg1); err != nil {
+		return nil, err
+	}
+
+	x2, err := randomScalar(q)
+	if err != nil {
+		return nil, err
+	}
+
+	q2 := ecc.BitcoingGenPoint.ScalarMul(x2)
+	proof2, err := proveDLog(x2, proofMessage("keygen/2", nil))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := send(t, &keyGenMsg2{Q2: encodePoint(q2), Proof: proof2}); err != nil {
+		return nil, err
+	}
+
+	var msg3 keyGenMsg3
+	if err := receive(t, &msg3); err != nil {
+		return nil, err
+	}
+
+	if err := verifyCommitment(msg1.Commitment, msg3.Nonce, []byte(msg3.Q1), msg3.Proof); err != nil {
+		return nil, err
+	}
+
+	q1, err := decodePoint(msg3.Q1)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ecc.VerifyDLogProof(q1, msg3.Proof, nil, proofMessage("keygen/1", nil)) {
+		return nil, fmt.Errorf("%w: party 1 key share", ErrInvalidProof)
+	}
+
+	if msg3.N == nil || msg3.N.BitLen() < MinPaillierBits {
+		return nil, fmt.Errorf("%w: modulus must have at least %d bits", ErrInvalidPaillierKey, MinPaillierBits)
+	}
+
+	paillier := NewPaillierPublicKey(msg3.N)
+	if !paillier.VerifyKeyProof(msg3.KeyProof) {
+		return nil, ErrInvalidPaillierKey
+	}
+
+	if !paillier.ValidCiphertext(msg3.CKey) {
+		return nil, ErrInvalidCiphertext
+	}
+
+	challenge := make([]byte, (rangeProofRounds+7)/8)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+
+	// a in Z_q and b in Z_q^2 mask x1 in the decryption of c'
+	a, err := randomScalar(q)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := randomScalar(big.NewInt(0).Mul(q, q))
+	if err != nil {
+		return nil, err
+	}
+
+	encB, _, err := paillier.Encrypt(b)
+	if err != nil {
+		return nil, err
+	}
+
+	abCommitment, abNonce, err := commit(a.Bytes(), b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := send(t, &keyGenMsg4{
+		RangeChallenge: challenge,
+		CPrime:         paillier.Add(paillier.Mul(msg3.CKey, a), encB),
+		ABCommitment:   abCommitment,
+	}); err != nil {
+		return nil, err
+	}
+
+	var msg5 keyGenMsg5
+	if err := receive(t, &msg5); err != nil {
+		return nil, err
+	}
+
+	l := big.NewInt(0).Div(q, big.NewInt(3))
+	if !verifyRangeProof(paillier, msg3.CKey, l, msg3.Range, challenge, msg5.Range) {
+		return nil, fmt.Errorf("%w: ckey is out of range", ErrInvalidProof)
+	}
+
+	if err := send(t, &keyGenMsg6{A: a, B: b, Nonce: abNonce}); err != nil {
+		return nil, err
+	}
+
+	var msg7 keyGenMsg7
+	if err := receive(t, &msg7); err != nil {
+		return nil, err
+	}
+
+	if err := verifyCommitment(msg5.QHatCommitment, msg7.Nonce, []byte(msg7.QHat)); err != nil {
+		return nil, err
+	}
+
+	// Q^ = a * Q1 + b * G only if ckey encrypts the discrete log of Q1
+	expected := q1.ScalarMul(a).Add(ecc.BitcoingGenPoint.ScalarMul(b))
+	if msg7.QHat != encodePoint(expected) {
+		return nil, fmt.Errorf("%w: ckey does not encrypt the discrete log of Q1", ErrInvalidProof)
+	}
+
+	return &Party2Key{
+		PublicKey: q1.ScalarMul(x2),
+		secret:    x2,
+		paillier:  paillier,
+		ckey:      msg3.CKey,
+	}, nil
+}
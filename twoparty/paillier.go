@@ -0,0 +1,247 @@
+package twoparty
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+const (
+	// MinPaillierBits is the smallest modulus accepted by the second
+	// party, it must exceed 2 * q ^ 4 for the soundness of the proofs
+	MinPaillierBits = 2048
+
+	// the number of N-th roots revealed by ProveKey and the bound of the
+	// small primes checked by VerifyKeyProof, as in Hazay et al.
+	keyProofRounds = 11
+	keyProofAlpha  = 6370
+)
+
+var (
+	ErrInvalidPaillierKey = errors.New("invalid paillier key")
+	ErrInvalidCiphertext  = errors.New("invalid paillier ciphertext")
+
+	bigOne = big.NewInt(1)
+)
+
+// PaillierPublicKey encrypts integers modulo N, ciphertexts are
+// additively homomorphic: Add and Mul work on the plaintexts
+type PaillierPublicKey struct {
+	N *big.Int
+
+	nSquared *big.Int
+}
+
+type PaillierPrivateKey struct {
+	PaillierPublicKey
+
+	// lambda = (p - 1) * (q - 1) and mu = lambda ^ -1 mod N
+	lambda *big.Int
+	mu     *big.Int
+
+	// the factors let encryptions run modulo p ^ 2 and q ^ 2
+	pSquared, qSquared *big.Int
+	pExp, qExp         *big.Int
+	qSquaredInv        *big.Int
+}
+
+func NewPaillierPublicKey(n *big.Int) *PaillierPublicKey {
+	return &PaillierPublicKey{N: n, nSquared: big.NewInt(0).Mul(n, n)}
+}
+
+// GeneratePaillierKey generates a key whose modulus has the given bits
+func GeneratePaillierKey(bits int) (*PaillierPrivateKey, error) {
+	for {
+		p, err := rand.Prime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, err
+		}
+
+		q, err := rand.Prime(rand.Reader, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+
+		n := big.NewInt(0).Mul(p, q)
+		if p.Cmp(q) == 0 || n.BitLen() != bits {
+			continue
+		}
+
+		pMinusOne := big.NewInt(0).Sub(p, bigOne)
+		qMinusOne := big.NewInt(0).Sub(q, bigOne)
+		lambda := big.NewInt(0).Mul(pMinusOne, qMinusOne)
+		mu := big.NewInt(0).ModInverse(lambda, n)
+		if mu == nil {
+			continue
+		}
+
+		pSquared := big.NewInt(0).Mul(p, p)
+		qSquared := big.NewInt(0).Mul(q, q)
+
+		return &PaillierPrivateKey{
+			PaillierPublicKey: *NewPaillierPublicKey(n),
+			lambda:            lambda,
+			mu:                mu,
+			pSquared:          pSquared,
+			qSquared:          qSquared,
+			// phi(p ^ 2) = p * (p - 1)
+			pExp:        big.NewInt(0).Mod(n, pMinusOne.Mul(pMinusOne, p)),
+			qExp:        big.NewInt(0).Mod(n, qMinusOne.Mul(qMinusOne, q)),
+			qSquaredInv: big.NewInt(0).ModInverse(qSquared, pSquared),
+		}, nil
+	}
+}
+
+// Encrypt encrypts m with a fresh nonce, which is returned
+// since the proofs about the ciphertext need it
+func (k *PaillierPublicKey) Encrypt(m *big.Int) (c, r *big.Int, err error) {
+	r, err = k.randomUnit()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return k.EncryptWithNonce(m, r), r, nil
+}
+
+// EncryptWithNonce computes (1 + N) ^ m * r ^ N mod N ^ 2
+func (k *PaillierPublicKey) EncryptWithNonce(m, r *big.Int) *big.Int {
+	// (1 + N) ^ m = 1 + m * N mod N ^ 2
+	c := big.NewInt(0).Mod(m, k.N)
+	c.Mul(c, k.N).Add(c, bigOne)
+
+	rn := big.NewInt(0).Exp(r, k.N, k.nSquared)
+	return c.Mul(c, rn).Mod(c, k.nSquared)
+}
+
+// Encrypt is PaillierPublicKey.Encrypt using the factorisation of N
+func (k *PaillierPrivateKey) Encrypt(m *big.Int) (c, r *big.Int, err error) {
+	r, err = k.randomUnit()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return k.EncryptWithNonce(m, r), r, nil
+}
+
+// EncryptWithNonce computes r ^ N modulo p ^ 2 and q ^ 2, about four
+// times faster than modulo N ^ 2, and combines them with the CRT
+func (k *PaillierPrivateKey) EncryptWithNonce(m, r *big.Int) *big.Int {
+	rp := big.NewInt(0).Exp(r, k.pExp, k.pSquared)
+	rq := big.NewInt(0).Exp(r, k.qExp, k.qSquared)
+
+	// rn = rq + q ^ 2 * ((rp - rq) * (q ^ 2) ^ -1 mod p ^ 2)
+	rn := rp.Sub(rp, rq).Mul(rp, k.qSquaredInv).Mod(rp, k.pSquared)
+	rn.Mul(rn, k.qSquared).Add(rn, rq)
+
+	c := big.NewInt(0).Mod(m, k.N)
+	c.Mul(c, k.N).Add(c, bigOne)
+	return c.Mul(c, rn).Mod(c, k.nSquared)
+}
+
+// Add returns an encryption of the sum of the plaintexts
+func (k *PaillierPublicKey) Add(c1, c2 *big.Int) *big.Int {
+	c := big.NewInt(0).Mul(c1, c2)
+	return c.Mod(c, k.nSquared)
+}
+
+// Mul returns an encryption of the plaintext times s
+func (k *PaillierPublicKey) Mul(c, s *big.Int) *big.Int {
+	return big.NewInt(0).Exp(c, s, k.nSquared)
+}
+
+// ValidCiphertext reports whether c is a unit of Z_{N^2}
+func (k *PaillierPublicKey) ValidCiphertext(c *big.Int) bool {
+	if c == nil || c.Sign() <= 0 || c.Cmp(k.nSquared) >= 0 {
+		return false
+	}
+
+	return big.NewInt(0).GCD(nil, nil, c, k.N).Cmp(bigOne) == 0
+}
+
+// Decrypt computes L(c ^ lambda mod N ^ 2) * mu mod N
+// where L(u) = (u - 1) / N
+func (k *PaillierPrivateKey) Decrypt(c *big.Int) (*big.Int, error) {
+	if !k.ValidCiphertext(c) {
+		return nil, ErrInvalidCiphertext
+	}
+
+	m := big.NewInt(0).Exp(c, k.lambda, k.nSquared)
+	m.Sub(m, bigOne).Div(m, k.N)
+	return m.Mul(m, k.mu).Mod(m, k.N), nil
+}
+
+// ProveKey proves that gcd(N, phi(N)) = 1 by revealing the N-th
+// roots of values derived from N, which only exist in that case
+func (k *PaillierPrivateKey) ProveKey() []*big.Int {
+	nInv := big.NewInt(0).ModInverse(k.N, k.lambda)
+
+	roots := make([]*big.Int, keyProofRounds)
+	for i := range roots {
+		roots[i] = big.NewInt(0).Exp(keyProofChallenge(k.N, i), nInv, k.N)
+	}
+
+	return roots
+}
+
+// VerifyKeyProof checks a proof generated by ProveKey, rejecting
+// moduli with small factors as required by the proof soundness
+func (k *PaillierPublicKey) VerifyKeyProof(roots []*big.Int) bool {
+	if k.N.Sign() <= 0 || len(roots) != keyProofRounds {
+		return false
+	}
+
+	rem := big.NewInt(0)
+	for p := int64(2); p < keyProofAlpha; p++ {
+		if big.NewInt(p).ProbablyPrime(0) && rem.Mod(k.N, big.NewInt(p)).Sign() == 0 {
+			return false
+		}
+	}
+
+	for i, root := range roots {
+		if root == nil || root.Sign() <= 0 || root.Cmp(k.N) >= 0 {
+			return false
+		}
+
+		if big.NewInt(0).Exp(root, k.N, k.N).Cmp(keyProofChallenge(k.N, i)) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// keyProofChallenge derives the i-th value in Z_N from N
+func keyProofChallenge(n *big.Int, i int) *big.Int {
+	var (
+		out     []byte
+		counter = make([]byte, 8)
+	)
+
+	for block := uint32(0); len(out) < (n.BitLen()+7)/8+16; block++ {
+		binary.BigEndian.PutUint32(counter, uint32(i))
+		binary.BigEndian.PutUint32(counter[4:], block)
+
+		h := sha256.New()
+		h.Write([]byte("twoparty/paillier-key"))
+		h.Write(n.Bytes())
+		h.Write(counter)
+		out = h.Sum(out)
+	}
+
+	return big.NewInt(0).Mod(big.NewInt(0).SetBytes(out), n)
+}
+
+func (k *PaillierPublicKey) randomUnit() (*big.Int, error) {
+	for {
+		r, err := rand.Int(rand.Reader, k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Sign() > 0 && big.NewInt(0).GCD(nil, nil, r, k.N).Cmp(bigOne) == 0 {
+			return r, nil
+		}
+	}
+}
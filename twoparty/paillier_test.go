@@ -0,0 +1,71 @@
+package twoparty_test
+
+import (
+	"math/big"
+	"testing"
+	"twoparty"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaillierHomomorphism(t *testing.T) {
+	key, err := twoparty.GeneratePaillierKey(512)
+	require.NoError(t, err)
+	require.Equal(t, 512, key.N.BitLen())
+
+	c1, r1, err := key.Encrypt(big.NewInt(1234))
+	require.NoError(t, err)
+	require.Equal(t, c1, key.EncryptWithNonce(big.NewInt(1234), r1))
+
+	// the private key encrypts through the CRT
+	require.Equal(t, c1, key.PaillierPublicKey.EncryptWithNonce(big.NewInt(1234), r1))
+
+	c2, _, err := key.Encrypt(big.NewInt(4321))
+	require.NoError(t, err)
+	require.NotEqual(t, c1, c2)
+
+	m, err := key.Decrypt(c1)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1234), m)
+
+	m, err = key.Decrypt(key.Add(c1, c2))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5555), m)
+
+	m, err = key.Decrypt(key.Mul(c1, big.NewInt(1000)))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1234000), m)
+
+	// plaintexts are reduced modulo N
+	minusOne := big.NewInt(0).Sub(key.N, big.NewInt(1))
+	c3, _, err := key.Encrypt(minusOne)
+	require.NoError(t, err)
+	m, err = key.Decrypt(key.Add(c1, c3))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1233), m)
+
+	_, err = key.Decrypt(big.NewInt(0))
+	require.ErrorIs(t, err, twoparty.ErrInvalidCiphertext)
+	_, err = key.Decrypt(big.NewInt(0).Mul(key.N, key.N))
+	require.ErrorIs(t, err, twoparty.ErrInvalidCiphertext)
+}
+
+func TestPaillierKeyProof(t *testing.T) {
+	key, err := twoparty.GeneratePaillierKey(512)
+	require.NoError(t, err)
+
+	proof := key.ProveKey()
+	require.True(t, key.VerifyKeyProof(proof))
+
+	other, err := twoparty.GeneratePaillierKey(512)
+	require.NoError(t, err)
+	require.False(t, other.VerifyKeyProof(proof))
+	require.False(t, key.VerifyKeyProof(proof[1:]))
+
+	// a modulus with a small factor is rejected
+	pub := twoparty.NewPaillierPublicKey(big.NewInt(0).Mul(key.N, big.NewInt(3)))
+	require.False(t, pub.VerifyKeyProof(proof))
+
+	tampered := append([]*big.Int{big.NewInt(0).Add(proof[0], big.NewInt(1))}, proof[1:]...)
+	require.False(t, key.VerifyKeyProof(tampered))
+}
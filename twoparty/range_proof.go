@@ -0,0 +1,184 @@
+package twoparty
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// The cut-and-choose range proof from the appendix of Lindell 2017: the
+// prover convinces the verifier that a paillier ciphertext c encrypts
+// some x in [-l, 2l] while knowing x in [0, l). Each round has a pair of
+// encryptions of w and w - l for a random w in [l, 2l), in random order.
+// A zero challenge bit opens both, a one bit reveals x + w_j for the j
+// that places the sum in [l, 2l). A cheating prover is caught with
+// probability 1 / 2 per round
+
+const rangeProofRounds = 40
+
+// rangeCommitment holds the encrypted pairs of every round
+type rangeCommitment struct {
+	C1 []*big.Int `json:"c1"`
+	C2 []*big.Int `json:"c2"`
+}
+
+// rangeResponse either opens both encryptions of a round or reveals
+// z = x + w_j encrypted by c * c_j with nonce r * r_j
+type rangeResponse struct {
+	W1 *big.Int `json:"w1,omitempty"`
+	R1 *big.Int `json:"r1,omitempty"`
+	W2 *big.Int `json:"w2,omitempty"`
+	R2 *big.Int `json:"r2,omitempty"`
+
+	J int      `json:"j,omitempty"`
+	Z *big.Int `json:"z,omitempty"`
+	R *big.Int `json:"r,omitempty"`
+}
+
+type rangeProver struct {
+	key  *PaillierPrivateKey
+	x, r *big.Int
+	l    *big.Int
+
+	w1, r1, w2, r2 []*big.Int
+}
+
+// newRangeProver commits to the rounds proving that c = Enc(x; r)
+// with x in [0, l) lies in [-l, 2l]
+func newRangeProver(key *PaillierPrivateKey, x, r, l *big.Int) (*rangeProver, *rangeCommitment, error) {
+	p := &rangeProver{
+		key: key, x: x, r: r, l: l,
+		w1: make([]*big.Int, rangeProofRounds),
+		r1: make([]*big.Int, rangeProofRounds),
+		w2: make([]*big.Int, rangeProofRounds),
+		r2: make([]*big.Int, rangeProofRounds),
+	}
+
+	swap := make([]byte, rangeProofRounds)
+	if _, err := rand.Read(swap); err != nil {
+		return nil, nil, err
+	}
+
+	commitment := &rangeCommitment{
+		C1: make([]*big.Int, rangeProofRounds),
+		C2: make([]*big.Int, rangeProofRounds),
+	}
+
+	for i := 0; i < rangeProofRounds; i++ {
+		w, err := rand.Int(rand.Reader, l)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		lo, hi := w, big.NewInt(0).Add(w, l)
+		if swap[i]&1 == 1 {
+			hi, lo = lo, hi
+		}
+
+		p.w1[i], p.w2[i] = hi, lo
+		if commitment.C1[i], p.r1[i], err = key.Encrypt(hi); err != nil {
+			return nil, nil, err
+		}
+
+		if commitment.C2[i], p.r2[i], err = key.Encrypt(lo); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return p, commitment, nil
+}
+
+func (p *rangeProver) respond(challenge []byte) []*rangeResponse {
+	responses := make([]*rangeResponse, rangeProofRounds)
+	for i := range responses {
+		if !challengeBit(challenge, i) {
+			responses[i] = &rangeResponse{W1: p.w1[i], R1: p.r1[i], W2: p.w2[i], R2: p.r2[i]}
+			continue
+		}
+
+		j, w, r := 1, p.w1[i], p.r1[i]
+		if z := big.NewInt(0).Add(p.x, p.w2[i]); inRange(z, p.l, big.NewInt(0).Lsh(p.l, 1)) {
+			j, w, r = 2, p.w2[i], p.r2[i]
+		}
+
+		nonce := big.NewInt(0).Mul(p.r, r)
+		responses[i] = &rangeResponse{
+			J: j,
+			Z: big.NewInt(0).Add(p.x, w),
+			R: nonce.Mod(nonce, p.key.N),
+		}
+	}
+
+	return responses
+}
+
+func verifyRangeProof(
+	key *PaillierPublicKey, c, l *big.Int,
+	commitment *rangeCommitment, challenge []byte, responses []*rangeResponse,
+) bool {
+	if commitment == nil || len(commitment.C1) != rangeProofRounds ||
+		len(commitment.C2) != rangeProofRounds || len(responses) != rangeProofRounds {
+		return false
+	}
+
+	twoL := big.NewInt(0).Lsh(l, 1)
+	for i, resp := range responses {
+		c1, c2 := commitment.C1[i], commitment.C2[i]
+		if resp == nil || !key.ValidCiphertext(c1) || !key.ValidCiphertext(c2) {
+			return false
+		}
+
+		if !challengeBit(challenge, i) {
+			if !validNonce(key, resp.R1) || !validNonce(key, resp.R2) || resp.W1 == nil || resp.W2 == nil {
+				return false
+			}
+
+			hi, lo := resp.W1, resp.W2
+			if hi.Cmp(lo) < 0 {
+				hi, lo = lo, hi
+			}
+
+			if !inRange(hi, l, twoL) || big.NewInt(0).Sub(hi, lo).Cmp(l) != 0 {
+				return false
+			}
+
+			if key.EncryptWithNonce(resp.W1, resp.R1).Cmp(c1) != 0 ||
+				key.EncryptWithNonce(resp.W2, resp.R2).Cmp(c2) != 0 {
+				return false
+			}
+
+			continue
+		}
+
+		cj := c1
+		switch resp.J {
+		case 1:
+		case 2:
+			cj = c2
+		default:
+			return false
+		}
+
+		if resp.Z == nil || !inRange(resp.Z, l, twoL) || !validNonce(key, resp.R) {
+			return false
+		}
+
+		if key.EncryptWithNonce(resp.Z, resp.R).Cmp(key.Add(c, cj)) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func challengeBit(challenge []byte, i int) bool {
+	return challenge[i/8]>>(i%8)&1 == 1
+}
+
+// inRange reports whether lo <= v < hi
+func inRange(v, lo, hi *big.Int) bool {
+	return v.Cmp(lo) >= 0 && v.Cmp(hi) < 0
+}
+
+func validNonce(key *PaillierPublicKey, r *big.Int) bool {
+	return r != nil && r.Sign() > 0 && r.Cmp(key.N) < 0
+}
@@ -0,0 +1,196 @@
+package twoparty
+
+import (
+	"fmt"
+	"math/big"
+
+	"ecc"
+)
+
+type signMsg1 struct {
+	Commitment []byte `json:"commitment"`
+}
+
+type signMsg2 struct {
+	R2    string `json:"r2"`
+	Proof []byte `json:"proof"`
+}
+
+type signMsg3 struct {
+	R1    string `json:"r1"`
+	Proof []byte `json:"proof"`
+	Nonce []byte `json:"nonce"`
+}
+
+// signMsg4 carries c3 = Enc(rho * q + k2 ^ -1 * (z + r * x1 * x2))
+type signMsg4 struct {
+	C3 *big.Int `json:"c3"`
+}
+
+// signMsg5 shares the resulting signature with the second party
+type signMsg5 struct {
+	Signature []byte `json:"signature"`
+}
+
+// Sign signs the message hash z with the second party, both parties
+// must agree on z beforehand. The signature has a low s
+func (k *Party1Key) Sign(t Transport, z *big.Int) (*ecc.Signature, error) {
+	q := ecc.BitcoinN
+
+	k1, err := randomScalar(q)
+	if err != nil {
+		return nil, err
+	}
+
+	r1 := ecc.BitcoingGenPoint.ScalarMul(k1)
+	proof1, err := proveDLog(k1, proofMessage("sign/1", z))
+	if err != nil {
+		return nil, err
+	}
+
+	commitment, nonce, err := commit([]byte(encodePoint(r1)), proof1)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := send(t, &signMsg1{Commitment: commitment}); err != nil {
+		return nil, err
+	}
+
+	var msg2 signMsg2
+	if err := receive(t, &msg2); err != nil {
+		return nil, err
+	}
+
+	r2, err := decodePoint(msg2.R2)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ecc.VerifyDLogProof(r2, msg2.Proof, nil, proofMessage("sign/2", z)) {
+		return nil, fmt.Errorf("%w: party 2 nonce", ErrInvalidProof)
+	}
+
+	if err := send(t, &signMsg3{R1: encodePoint(r1), Proof: proof1, Nonce: nonce}); err != nil {
+		return nil, err
+	}
+
+	var msg4 signMsg4
+	if err := receive(t, &msg4); err != nil {
+		return nil, err
+	}
+
+	// s = k1 ^ -1 * Dec(c3) mod q
+	s, err := k.paillier.Decrypt(msg4.C3)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Mod(s, q).Mul(s, big.NewInt(0).ModInverse(k1, q)).Mod(s, q)
+	if s.Cmp(big.NewInt(0).Rsh(q, 1)) > 0 {
+		s.Sub(q, s)
+	}
+
+	r := xCoordinate(r2.ScalarMul(k1))
+	if r.Sign() == 0 || s.Sign() == 0 {
+		return nil, ErrInvalidSignature
+	}
+
+	sig := ecc.NewSignature(ecc.NewFieldElement(q, r), ecc.NewFieldElement(q, s))
+	if !k.PublicKey.Verify(ecc.NewFieldElement(q, big.NewInt(0).Mod(z, q)), sig) {
+		return nil, ErrInvalidSignature
+	}
+
+	if err := send(t, &signMsg5{Signature: sig.Der()}); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// Sign signs the message hash z with the first party, which is the
+// only one able to compute the signature and sends it back
+func (k *Party2Key) Sign(t Transport, z *big.Int) (*ecc.Signature, error) {
+	q := ecc.BitcoinN
+
+	var msg1 signMsg1
+	if err := receive(t, &msg1); err != nil {
+		return nil, err
+	}
+
+	k2, err := randomScalar(q)
+	if err != nil {
+		return nil, err
+	}
+
+	r2 := ecc.BitcoingGenPoint.ScalarMul(k2)
+	proof2, err := proveDLog(k2, proofMessage("sign/2", z))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := send(t, &signMsg2{R2: encodePoint(r2), Proof: proof2}); err != nil {
+		return nil, err
+	}
+
+	var msg3 signMsg3
+	if err := receive(t, &msg3); err != nil {
+		return nil, err
+	}
+
+	if err := verifyCommitment(msg1.Commitment, msg3.Nonce, []byte(msg3.R1), msg3.Proof); err != nil {
+		return nil, err
+	}
+
+	r1, err := decodePoint(msg3.R1)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ecc.VerifyDLogProof(r1, msg3.Proof, nil, proofMessage("sign/1", z)) {
+		return nil, fmt.Errorf("%w: party 1 nonce", ErrInvalidProof)
+	}
+
+	r := xCoordinate(r1.ScalarMul(k2))
+	if r.Sign() == 0 {
+		return nil, ErrInvalidSignature
+	}
+
+	// rho * q statistically hides the multiple of q in the plaintext
+	rho, err := randomScalar(big.NewInt(0).Mul(q, q))
+	if err != nil {
+		return nil, err
+	}
+
+	k2Inv := big.NewInt(0).ModInverse(k2, q)
+
+	m := big.NewInt(0).Mul(k2Inv, z)
+	m.Mod(m, q).Add(m, rho.Mul(rho, q))
+	c1, _, err := k.paillier.Encrypt(m)
+	if err != nil {
+		return nil, err
+	}
+
+	v := big.NewInt(0).Mul(k2Inv, r)
+	v.Mul(v, k.secret).Mod(v, q)
+
+	if err := send(t, &signMsg4{C3: k.paillier.Add(c1, k.paillier.Mul(k.ckey, v))}); err != nil {
+		return nil, err
+	}
+
+	var msg5 signMsg5
+	if err := receive(t, &msg5); err != nil {
+		return nil, err
+	}
+
+	sig, err := ecc.ParseDER(msg5.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if sig.R().Cmp(r) != 0 || !k.PublicKey.Verify(ecc.NewFieldElement(q, big.NewInt(0).Mod(z, q)), sig) {
+		return nil, ErrInvalidSignature
+	}
+
+	return sig, nil
+}
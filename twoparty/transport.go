@@ -0,0 +1,87 @@
+package twoparty
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+var ErrClosed = errors.New("transport is closed")
+
+// Transport carries the protocol messages between the two parties, it
+// must deliver them in order. Messages are JSON encoded
+type Transport interface {
+	Send(msg []byte) error
+	Receive() ([]byte, error)
+	Close() error
+}
+
+// pipe is one end of an in-process transport
+type pipe struct {
+	in  <-chan []byte
+	out chan<- []byte
+
+	closed chan struct{}
+	once   *sync.Once
+}
+
+// NewPipe returns the two ends of an in-process transport, closing
+// either end unblocks and fails every pending and future call
+func NewPipe() (Transport, Transport) {
+	var (
+		a2b    = make(chan []byte, 16)
+		b2a    = make(chan []byte, 16)
+		closed = make(chan struct{})
+		once   = new(sync.Once)
+	)
+
+	return &pipe{in: b2a, out: a2b, closed: closed, once: once},
+		&pipe{in: a2b, out: b2a, closed: closed, once: once}
+}
+
+func (p *pipe) Send(msg []byte) error {
+	select {
+	case <-p.closed:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case p.out <- append([]byte{}, msg...):
+		return nil
+	case <-p.closed:
+		return ErrClosed
+	}
+}
+
+func (p *pipe) Receive() ([]byte, error) {
+	select {
+	case msg := <-p.in:
+		return msg, nil
+	case <-p.closed:
+		return nil, ErrClosed
+	}
+}
+
+func (p *pipe) Close() error {
+	p.once.Do(func() { close(p.closed) })
+	return nil
+}
+
+func send(t Transport, msg any) error {
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return t.Send(encoded)
+}
+
+func receive(t Transport, msg any) error {
+	encoded, err := t.Receive()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, msg)
+}
@@ -0,0 +1,125 @@
+// Package twoparty implements the two-party ECDSA protocol of Lindell
+// 2017, "Fast Secure Two-Party ECDSA Signing". The parties hold the
+// multiplicative shares x1 and x2 of the secret behind Q = x1 * x2 * G
+// and jointly produce standard ECDSA signatures without reconstructing
+// it. The first party also holds a paillier key and the second party an
+// encryption of x1 under it, which lets the second party compute an
+// encryption of the signature that only the first party can finish
+package twoparty
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"ecc"
+)
+
+var (
+	ErrInvalidCommitment = errors.New("commitment does not match the opened values")
+	ErrInvalidProof      = errors.New("invalid proof")
+	ErrInvalidSignature  = errors.New("protocol produced an invalid signature")
+)
+
+// commit returns a hash commitment to parts and the nonce opening it
+func commit(parts ...[]byte) (commitment, nonce []byte, err error) {
+	nonce = make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	return commitmentHash(nonce, parts...), nonce, nil
+}
+
+func verifyCommitment(commitment, nonce []byte, parts ...[]byte) error {
+	if len(nonce) != 32 || subtle.ConstantTimeCompare(commitment, commitmentHash(nonce, parts...)) != 1 {
+		return ErrInvalidCommitment
+	}
+
+	return nil
+}
+
+// commitmentHash length prefixes every part so the encoding is unambiguous
+func commitmentHash(nonce []byte, parts ...[]byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("twoparty/commitment"))
+	h.Write(nonce)
+
+	size := make([]byte, 8)
+	for _, part := range parts {
+		binary.BigEndian.PutUint64(size, uint64(len(part)))
+		h.Write(size)
+		h.Write(part)
+	}
+
+	return h.Sum(nil)
+}
+
+// proofMessage binds the proofs of knowledge to the protocol step
+// and to the signed message, if any
+func proofMessage(label string, z *big.Int) []byte {
+	h := sha256.New()
+	h.Write([]byte("twoparty/" + label))
+	if z != nil {
+		h.Write(z.Bytes())
+	}
+
+	return h.Sum(nil)
+}
+
+// proveDLog proves the knowledge of secret for secret * G
+func proveDLog(secret *big.Int, msg []byte) ([]byte, error) {
+	aux := make([]byte, 32)
+	if _, err := rand.Read(aux); err != nil {
+		return nil, err
+	}
+
+	return ecc.NewPrivateKey(secret).DLogProof(nil, aux, msg)
+}
+
+// randomScalar returns a random integer in [1, max)
+func randomScalar(max *big.Int) (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return nil, err
+		}
+
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+// encodePoint returns the hex of the compressed SEC encoding of p
+func encodePoint(p *ecc.Point) string {
+	text, err := p.MarshalText()
+	if err != nil {
+		panic(err)
+	}
+
+	return string(text)
+}
+
+func decodePoint(encoded string) (*ecc.Point, error) {
+	p := new(ecc.Point)
+	if err := p.UnmarshalText([]byte(encoded)); err != nil {
+		return nil, fmt.Errorf("invalid point: %w", err)
+	}
+
+	if p.IsInfinity() {
+		return nil, fmt.Errorf("invalid point: %w", ecc.ErrInfinity)
+	}
+
+	return p, nil
+}
+
+// xCoordinate returns the x coordinate of p reduced modulo n
+func xCoordinate(p *ecc.Point) *big.Int {
+	x := p.X()
+	return x.Mod(x, ecc.BitcoinN)
+}
@@ -0,0 +1,180 @@
+package twoparty_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+	"twoparty"
+
+	"ecc"
+
+	"github.com/stretchr/testify/require"
+)
+
+// run executes both parties, closing the transport when either
+// fails so the other one does not block forever
+func run[T1, T2 any](
+	t1, t2 twoparty.Transport,
+	party1 func(twoparty.Transport) (T1, error),
+	party2 func(twoparty.Transport) (T2, error),
+) (r1 T1, err1 error, r2 T2, err2 error) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if r1, err1 = party1(t1); err1 != nil {
+			t1.Close()
+		}
+	}()
+
+	if r2, err2 = party2(t2); err2 != nil {
+		t2.Close()
+	}
+
+	wg.Wait()
+	return r1, err1, r2, err2
+}
+
+func keyGen(t1, t2 twoparty.Transport) (*twoparty.Party1Key, error, *twoparty.Party2Key, error) {
+	return run(t1, t2,
+		func(t twoparty.Transport) (*twoparty.Party1Key, error) {
+			return twoparty.KeyGenParty1(t, twoparty.MinPaillierBits)
+		},
+		twoparty.KeyGenParty2,
+	)
+}
+
+func sign(p1 *twoparty.Party1Key, p2 *twoparty.Party2Key, z1, z2 *big.Int) (*ecc.Signature, error, *ecc.Signature, error) {
+	t1, t2 := twoparty.NewPipe()
+	return run(t1, t2,
+		func(t twoparty.Transport) (*ecc.Signature, error) { return p1.Sign(t, z1) },
+		func(t twoparty.Transport) (*ecc.Signature, error) { return p2.Sign(t, z2) },
+	)
+}
+
+func TestKeyGenAndSign(t *testing.T) {
+	p1, err1, p2, err2 := keyGen(twoparty.NewPipe())
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	require.True(t, p1.PublicKey.EqualTo(p2.PublicKey))
+
+	halfN := big.NewInt(0).Rsh(ecc.BitcoinN, 1)
+	for _, msg := range []string{"first", "second", "third"} {
+		h := sha256.Sum256([]byte(msg))
+		z := big.NewInt(0).SetBytes(h[:])
+
+		sig1, err1, sig2, err2 := sign(p1, p2, z, z)
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.Equal(t, sig1.Der(), sig2.Der())
+		require.LessOrEqual(t, sig1.S().Cmp(halfN), 0)
+
+		zField := ecc.NewFieldElement(ecc.BitcoinN, big.NewInt(0).Mod(z, ecc.BitcoinN))
+		require.True(t, p1.PublicKey.Verify(zField, sig1))
+	}
+
+	// the parties must agree on the message
+	_, err1, _, err2 = sign(p1, p2, big.NewInt(1), big.NewInt(2))
+	require.ErrorIs(t, err1, twoparty.ErrInvalidProof)
+	require.Error(t, err2)
+}
+
+// tamperTransport rewrites the n-th message sent through it
+type tamperTransport struct {
+	twoparty.Transport
+
+	sent   int
+	target int
+	tamper func(map[string]json.RawMessage)
+}
+
+func (t *tamperTransport) Send(msg []byte) error {
+	t.sent++
+	if t.sent != t.target {
+		return t.Transport.Send(msg)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &fields); err != nil {
+		return err
+	}
+
+	t.tamper(fields)
+	tampered, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return t.Transport.Send(tampered)
+}
+
+func TestKeyGenRejectsTamperedMessages(t *testing.T) {
+	cases := map[string]struct {
+		tamper   func(map[string]json.RawMessage)
+		expected error
+	}{
+		"opened share differs from the commitment": {
+			tamper: func(fields map[string]json.RawMessage) {
+				fields["q1"] = json.RawMessage(`"` + ecc.BitcoingGenPoint.Sec(true) + `"`)
+			},
+			expected: twoparty.ErrInvalidCommitment,
+		},
+		"ckey encrypts another value": {
+			tamper: func(fields map[string]json.RawMessage) {
+				var n, ckey big.Int
+				require.NoError(t, json.Unmarshal(fields["n"], &n))
+				require.NoError(t, json.Unmarshal(fields["ckey"], &ckey))
+
+				// multiplying by 1 + N adds one to the plaintext
+				nSquared := big.NewInt(0).Mul(&n, &n)
+				ckey.Mul(&ckey, big.NewInt(0).Add(&n, big.NewInt(1))).Mod(&ckey, nSquared)
+				fields["ckey"], _ = json.Marshal(&ckey)
+			},
+			expected: twoparty.ErrInvalidProof,
+		},
+		"paillier key proof for another modulus": {
+			tamper: func(fields map[string]json.RawMessage) {
+				var n big.Int
+				require.NoError(t, json.Unmarshal(fields["n"], &n))
+				fields["n"], _ = json.Marshal(n.Add(&n, big.NewInt(2)))
+			},
+			expected: twoparty.ErrInvalidPaillierKey,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t1, t2 := twoparty.NewPipe()
+			tampered := &tamperTransport{Transport: t1, target: 2, tamper: c.tamper}
+
+			_, err1, _, err2 := keyGen(tampered, t2)
+			require.ErrorIs(t, err2, c.expected)
+			require.ErrorIs(t, err1, twoparty.ErrClosed)
+		})
+	}
+}
+
+func TestPipe(t *testing.T) {
+	t1, t2 := twoparty.NewPipe()
+
+	msg := []byte("hello")
+	require.NoError(t, t1.Send(msg))
+	msg[0] = 'j'
+
+	received, err := t2.Receive()
+	require.NoError(t, err)
+	require.True(t, bytes.Equal([]byte("hello"), received))
+
+	done := make(chan error)
+	go func() {
+		_, err := t1.Receive()
+		done <- err
+	}()
+
+	require.NoError(t, t2.Close())
+	require.ErrorIs(t, <-done, twoparty.ErrClosed)
+	require.ErrorIs(t, t1.Send(msg), twoparty.ErrClosed)
+}
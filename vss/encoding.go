@@ -0,0 +1,137 @@
+package vss
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"ecc"
+)
+
+// Shares are encoded as the 4 bytes big endian index followed by the
+// 32 bytes big endian value. Commitments are encoded as the
+// concatenation of the 33 bytes compressed SEC encoding of every point.
+// The text encoding of both is the hex encoding of the binary one, so
+// they can be embedded in JSON documents
+
+const (
+	ShareSize      = 36
+	CommitmentSize = 33
+)
+
+var ErrInvalidEncoding = errors.New("invalid encoding")
+
+func (s *Share) MarshalBinary() ([]byte, error) {
+	if s.Value == nil || s.Value.Sign() < 0 || s.Value.Cmp(ecc.BitcoinN) >= 0 {
+		return nil, fmt.Errorf("%w: share value out of range", ErrInvalidEncoding)
+	}
+
+	buf := make([]byte, ShareSize)
+	binary.BigEndian.PutUint32(buf, s.Index)
+	s.Value.FillBytes(buf[4:])
+	return buf, nil
+}
+
+func (s *Share) UnmarshalBinary(data []byte) error {
+	if len(data) != ShareSize {
+		return fmt.Errorf("%w: share must be %d bytes long", ErrInvalidEncoding, ShareSize)
+	}
+
+	index := binary.BigEndian.Uint32(data)
+	value := big.NewInt(0).SetBytes(data[4:])
+	if index == 0 || value.Cmp(ecc.BitcoinN) >= 0 {
+		return fmt.Errorf("%w: share index or value out of range", ErrInvalidEncoding)
+	}
+
+	s.Index, s.Value = index, value
+	return nil
+}
+
+func (s *Share) MarshalText() ([]byte, error) {
+	return marshalText(s.MarshalBinary())
+}
+
+func (s *Share) UnmarshalText(text []byte) error {
+	data, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+
+	return s.UnmarshalBinary(data)
+}
+
+func (c Commitments) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, len(c)*CommitmentSize)
+	for _, p := range c {
+		if p == nil || p.IsInfinity() {
+			return nil, fmt.Errorf("%w: commitment is the point at infinity", ErrInvalidEncoding)
+		}
+
+		sec, err := p.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+		}
+
+		buf = append(buf, sec...)
+	}
+
+	return buf, nil
+}
+
+func (c *Commitments) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || len(data)%CommitmentSize != 0 {
+		return fmt.Errorf("%w: commitments must be a multiple of %d bytes long", ErrInvalidEncoding, CommitmentSize)
+	}
+
+	commitments := make(Commitments, 0, len(data)/CommitmentSize)
+	for i := 0; i < len(data); i += CommitmentSize {
+		sec := data[i : i+CommitmentSize]
+		if sec[0] != 0x02 && sec[0] != 0x03 {
+			return fmt.Errorf("%w: commitment %d is not compressed", ErrInvalidEncoding, i/CommitmentSize)
+		}
+
+		p := new(ecc.Point)
+		if err := p.UnmarshalBinary(sec); err != nil {
+			return fmt.Errorf("%w: commitment %d: %w", ErrInvalidEncoding, i/CommitmentSize, err)
+		}
+
+		commitments = append(commitments, p)
+	}
+
+	*c = commitments
+	return nil
+}
+
+func (c Commitments) MarshalText() ([]byte, error) {
+	return marshalText(c.MarshalBinary())
+}
+
+func (c *Commitments) UnmarshalText(text []byte) error {
+	data, err := unmarshalText(text)
+	if err != nil {
+		return err
+	}
+
+	return c.UnmarshalBinary(data)
+}
+
+func marshalText(data []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	text := make([]byte, hex.EncodedLen(len(data)))
+	hex.Encode(text, data)
+	return text, nil
+}
+
+func unmarshalText(text []byte) ([]byte, error) {
+	data := make([]byte, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(data, text); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,100 @@
+package vss_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"vss"
+
+	"ecc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareEncoding(t *testing.T) {
+	share := &vss.Share{Index: 7, Value: big.NewInt(0xabcdef)}
+
+	data, err := share.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, "00000007"+strings.Repeat("0", 58)+"abcdef", hex.EncodeToString(data))
+
+	var decoded vss.Share
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, *share, decoded)
+
+	text, err := share.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(data), string(text))
+
+	invalid := map[string]string{
+		"short":        hex.EncodeToString(data[:35]),
+		"zero index":   "00000000" + hex.EncodeToString(data[4:]),
+		"value over n": "00000001" + "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+		"not hex":      "zz",
+	}
+
+	for name, text := range invalid {
+		require.ErrorIs(t, decoded.UnmarshalText([]byte(text)), vss.ErrInvalidEncoding, name)
+	}
+
+	_, err = (&vss.Share{Index: 1, Value: ecc.BitcoinN}).MarshalBinary()
+	require.ErrorIs(t, err, vss.ErrInvalidEncoding)
+}
+
+func TestCommitmentsEncoding(t *testing.T) {
+	key := ecc.NewPrivateKey(big.NewInt(1))
+	shares, commitments, err := vss.Split(key, 3, 4)
+	require.NoError(t, err)
+
+	data, err := commitments.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, 3*vss.CommitmentSize)
+	require.Equal(t, ecc.BitcoingGenPoint.Sec(true), hex.EncodeToString(data[:vss.CommitmentSize]))
+
+	var decoded vss.Commitments
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, commitments.Threshold(), decoded.Threshold())
+	for _, share := range shares {
+		require.True(t, decoded.Verify(share))
+	}
+
+	require.ErrorIs(t, decoded.UnmarshalBinary(data[1:]), vss.ErrInvalidEncoding)
+	require.ErrorIs(t, decoded.UnmarshalBinary(nil), vss.ErrInvalidEncoding)
+
+	// uncompressed points and x coordinates off the curve are rejected
+	bad := append([]byte{}, data...)
+	bad[0] = 0x04
+	require.ErrorIs(t, decoded.UnmarshalBinary(bad), vss.ErrInvalidEncoding)
+
+	offCurve, err := hex.DecodeString("02" + strings.Repeat("0", 63) + "5")
+	require.NoError(t, err)
+	require.ErrorIs(t, decoded.UnmarshalBinary(offCurve), vss.ErrInvalidEncoding)
+}
+
+func TestJSONBackup(t *testing.T) {
+	key := ecc.NewPrivateKey(big.NewInt(0xdeadbeef))
+	shares, commitments, err := vss.Split(key, 2, 3)
+	require.NoError(t, err)
+
+	type backup struct {
+		Commitments vss.Commitments `json:"commitments"`
+		Share       *vss.Share      `json:"share"`
+	}
+
+	var decoded []backup
+	for _, share := range shares {
+		encoded, err := json.Marshal(backup{Commitments: commitments, Share: share})
+		require.NoError(t, err)
+
+		var b backup
+		require.NoError(t, json.Unmarshal(encoded, &b))
+		require.True(t, b.Commitments.Verify(b.Share))
+		decoded = append(decoded, b)
+	}
+
+	recovered, err := vss.Combine([]*vss.Share{decoded[2].Share, decoded[0].Share}, decoded[1].Commitments)
+	require.NoError(t, err)
+	require.Equal(t, key.Secret(), recovered.Secret())
+}
@@ -0,0 +1,172 @@
+// Package vss implements Feldman's verifiable secret sharing of private
+// keys. The secret is the constant term of a random polynomial f of
+// degree t - 1 over the scalar field, the holder i receives f(i) and
+// everyone gets the commitments a_j * G to the coefficients, which let
+// each holder check its share without learning the others
+package vss
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"ecc"
+)
+
+var (
+	ErrInvalidThreshold = errors.New("threshold must be in the range [1, n]")
+	ErrInvalidShare     = errors.New("share does not match the commitments")
+	ErrDuplicateShare   = errors.New("duplicated share index")
+	ErrNotEnoughShares  = errors.New("not enough shares to recover the secret")
+)
+
+// Share is the evaluation of the polynomial at Index, which is never zero
+type Share struct {
+	Index uint32
+	Value *big.Int
+}
+
+// Commitments holds a_j * G for every coefficient a_j of the polynomial,
+// the first one is the public key of the shared secret
+type Commitments []*ecc.Point
+
+// Split shares the secret of key between n holders, any threshold
+// of them can recover it
+func Split(key *ecc.PrivateKey, threshold, n int) ([]*Share, Commitments, error) {
+	if threshold < 1 || threshold > n || int64(n) > int64(^uint32(0)) {
+		return nil, nil, ErrInvalidThreshold
+	}
+
+	coefficients := make([]*big.Int, threshold)
+	coefficients[0] = key.Secret()
+	for j := 1; j < threshold; j++ {
+		a, err := rand.Int(rand.Reader, ecc.BitcoinN)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		coefficients[j] = a
+	}
+
+	commitments := make(Commitments, threshold)
+	for j, a := range coefficients {
+		commitments[j] = ecc.BitcoingGenPoint.ScalarMul(a)
+	}
+
+	shares := make([]*Share, n)
+	for i := range shares {
+		index := uint32(i + 1)
+		shares[i] = &Share{Index: index, Value: evaluate(coefficients, index)}
+	}
+
+	return shares, commitments, nil
+}
+
+// Threshold returns the number of shares needed to recover the secret
+func (c Commitments) Threshold() int {
+	return len(c)
+}
+
+func (c Commitments) PublicKey() *ecc.Point {
+	return c[0]
+}
+
+// Verify checks f(i) * G = sum(C_j * i ^ j)
+func (c Commitments) Verify(share *Share) bool {
+	if len(c) == 0 || share == nil || share.Index == 0 || share.Value == nil ||
+		share.Value.Sign() < 0 || share.Value.Cmp(ecc.BitcoinN) >= 0 {
+		return false
+	}
+
+	var (
+		index    = big.NewInt(int64(share.Index))
+		power    = big.NewInt(1)
+		expected = c[0]
+	)
+
+	for _, commitment := range c[1:] {
+		power.Mul(power, index).Mod(power, ecc.BitcoinN)
+		expected = expected.Add(commitment.ScalarMul(power))
+	}
+
+	return ecc.BitcoingGenPoint.ScalarMul(share.Value).EqualTo(expected)
+}
+
+// Combine verifies the shares and recovers the secret with lagrange
+// interpolation at zero, using the first threshold valid shares
+func Combine(shares []*Share, commitments Commitments) (*ecc.PrivateKey, error) {
+	if len(commitments) == 0 {
+		return nil, ErrInvalidThreshold
+	}
+
+	if len(shares) < commitments.Threshold() {
+		return nil, ErrNotEnoughShares
+	}
+
+	seen := make(map[uint32]bool)
+	for _, share := range shares {
+		if !commitments.Verify(share) {
+			return nil, fmt.Errorf("%w: index %d", ErrInvalidShare, shareIndex(share))
+		}
+
+		if seen[share.Index] {
+			return nil, fmt.Errorf("%w: index %d", ErrDuplicateShare, share.Index)
+		}
+
+		seen[share.Index] = true
+	}
+
+	secret := Interpolate(shares[:commitments.Threshold()])
+	key := ecc.NewPrivateKey(secret)
+	if !key.PublicKey().EqualTo(commitments.PublicKey()) {
+		return nil, ErrInvalidShare
+	}
+
+	return key, nil
+}
+
+// Interpolate returns f(0) for the polynomial passing through the
+// shares, which must have distinct indexes. Without commitments
+// nothing tells whether the result is the shared secret
+func Interpolate(shares []*Share) *big.Int {
+	secret := big.NewInt(0)
+	for i, share := range shares {
+		// l_i(0) = prod(x_j / (x_j - x_i)) for j != i
+		num, den := big.NewInt(1), big.NewInt(1)
+		xi := big.NewInt(int64(share.Index))
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+
+			xj := big.NewInt(int64(other.Index))
+			num.Mul(num, xj).Mod(num, ecc.BitcoinN)
+			den.Mul(den, xj.Sub(xj, xi)).Mod(den, ecc.BitcoinN)
+		}
+
+		term := num.Mul(num, den.ModInverse(den, ecc.BitcoinN))
+		term.Mul(term, share.Value)
+		secret.Add(secret, term).Mod(secret, ecc.BitcoinN)
+	}
+
+	return secret
+}
+
+// evaluate computes f(x) with horner's method
+func evaluate(coefficients []*big.Int, x uint32) *big.Int {
+	result := big.NewInt(0)
+	for j := len(coefficients) - 1; j >= 0; j-- {
+		result.Mul(result, big.NewInt(int64(x))).Add(result, coefficients[j]).Mod(result, ecc.BitcoinN)
+	}
+
+	return result
+}
+
+func shareIndex(share *Share) uint32 {
+	if share == nil {
+		return 0
+	}
+
+	return share.Index
+}
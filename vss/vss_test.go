@@ -0,0 +1,92 @@
+package vss_test
+
+import (
+	"math/big"
+	"testing"
+	"vss"
+
+	"ecc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAndCombine(t *testing.T) {
+	secret, _ := big.NewInt(0).SetString("a6b1c2d3e4f5061728394a5b6c7d8e9f00112233445566778899aabbccddeeff", 16)
+	key := ecc.NewPrivateKey(secret)
+
+	shares, commitments, err := vss.Split(key, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+	require.Equal(t, 3, commitments.Threshold())
+	require.True(t, key.PublicKey().EqualTo(commitments.PublicKey()))
+
+	for i, share := range shares {
+		require.Equal(t, uint32(i+1), share.Index)
+		require.True(t, commitments.Verify(share))
+	}
+
+	// any three shares recover the secret
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}} {
+		picked := make([]*vss.Share, len(subset))
+		for i, idx := range subset {
+			picked[i] = shares[idx]
+		}
+
+		recovered, err := vss.Combine(picked, commitments)
+		require.NoError(t, err)
+		require.Equal(t, secret, recovered.Secret())
+	}
+
+	// two shares are not enough, interpolating them gives another value
+	_, err = vss.Combine(shares[:2], commitments)
+	require.ErrorIs(t, err, vss.ErrNotEnoughShares)
+	require.NotEqual(t, secret, vss.Interpolate(shares[:2]))
+}
+
+func TestVerifyRejectsInvalidShares(t *testing.T) {
+	key := ecc.NewPrivateKey(big.NewInt(123456789))
+	shares, commitments, err := vss.Split(key, 2, 3)
+	require.NoError(t, err)
+
+	tampered := &vss.Share{Index: shares[0].Index, Value: big.NewInt(0).Add(shares[0].Value, big.NewInt(1))}
+	require.False(t, commitments.Verify(tampered))
+	require.False(t, commitments.Verify(&vss.Share{Index: 0, Value: key.Secret()}))
+	require.False(t, commitments.Verify(&vss.Share{Index: 2, Value: ecc.BitcoinN}))
+	require.False(t, commitments.Verify(&vss.Share{Index: 1, Value: big.NewInt(0)}))
+	require.False(t, commitments.Verify(nil))
+
+	// a share checked against another dealing fails
+	_, others, err := vss.Split(key, 2, 3)
+	require.NoError(t, err)
+	require.False(t, others.Verify(shares[1]))
+
+	_, err = vss.Combine([]*vss.Share{shares[0], tampered}, commitments)
+	require.ErrorIs(t, err, vss.ErrInvalidShare)
+
+	_, err = vss.Combine([]*vss.Share{shares[1], shares[1]}, commitments)
+	require.ErrorIs(t, err, vss.ErrDuplicateShare)
+}
+
+func TestSplitThresholds(t *testing.T) {
+	key := ecc.NewPrivateKey(big.NewInt(42))
+
+	for _, c := range [][2]int{{0, 3}, {4, 3}, {-1, 1}} {
+		_, _, err := vss.Split(key, c[0], c[1])
+		require.ErrorIs(t, err, vss.ErrInvalidThreshold)
+	}
+
+	// with a threshold of one every share is the secret
+	shares, commitments, err := vss.Split(key, 1, 3)
+	require.NoError(t, err)
+	for _, share := range shares {
+		require.Equal(t, big.NewInt(42), share.Value)
+		require.True(t, commitments.Verify(share))
+	}
+
+	// n of n
+	shares, commitments, err = vss.Split(key, 4, 4)
+	require.NoError(t, err)
+	recovered, err := vss.Combine(shares, commitments)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(42), recovered.Secret())
+}
@@ -0,0 +1,85 @@
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// CompactSize is the variable length integer prefixing lengths, values
+// below 0xfd are a single byte, larger ones a 0xfd, 0xfe or 0xff marker
+// followed by 2, 4 or 8 bytes. Only the shortest encoding is canonical
+
+const (
+	compactSize16 = 0xfd
+	compactSize32 = 0xfe
+	compactSize64 = 0xff
+)
+
+// CompactSizeLen returns the size of the encoding of n
+func CompactSizeLen(n uint64) int {
+	switch {
+	case n < compactSize16:
+		return 1
+	case n <= 0xffff:
+		return 3
+	case n <= 0xffffffff:
+		return 5
+	default:
+		return 9
+	}
+}
+
+func AppendCompactSize(b []byte, n uint64) []byte {
+	switch {
+	case n < compactSize16:
+		return append(b, byte(n))
+	case n <= 0xffff:
+		return binary.LittleEndian.AppendUint16(append(b, compactSize16), uint16(n))
+	case n <= 0xffffffff:
+		return binary.LittleEndian.AppendUint32(append(b, compactSize32), uint32(n))
+	default:
+		return binary.LittleEndian.AppendUint64(append(b, compactSize64), n)
+	}
+}
+
+func WriteCompactSize(w io.Writer, n uint64) error {
+	_, err := w.Write(AppendCompactSize(make([]byte, 0, 9), n))
+	return err
+}
+
+// ReadCompactSize reads a canonically encoded CompactSize, a value that
+// fits a shorter encoding fails with ErrNonCanonical
+func ReadCompactSize(r io.Reader) (uint64, error) {
+	marker, err := ReadUint8(r)
+	if err != nil {
+		return 0, err
+	}
+
+	var n, least uint64
+	switch marker {
+	case compactSize16:
+		var v uint16
+		v, err = ReadUint16(r)
+		n, least = uint64(v), compactSize16
+	case compactSize32:
+		var v uint32
+		v, err = ReadUint32(r)
+		n, least = uint64(v), 0x10000
+	case compactSize64:
+		n, err = ReadUint64(r)
+		least = 0x100000000
+	default:
+		return uint64(marker), nil
+	}
+
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+
+	if n < least {
+		return 0, fmt.Errorf("%w: %d encoded with marker %#02x", ErrNonCanonical, n, marker)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,81 @@
+package wire_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+	"wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+var compactSizeVectors = []struct {
+	n       uint64
+	encoded string
+}{
+	{0, "00"},
+	{1, "01"},
+	{0xfc, "fc"},
+	{0xfd, "fdfd00"},
+	{0x1234, "fd3412"},
+	{0xffff, "fdffff"},
+	{0x10000, "fe00000100"},
+	{0xffffffff, "feffffffff"},
+	{0x100000000, "ff0000000001000000"},
+	{0xffffffffffffffff, "ffffffffffffffffff"},
+}
+
+func TestCompactSize(t *testing.T) {
+	for _, v := range compactSizeVectors {
+		require.Equal(t, v.encoded, hex.EncodeToString(wire.AppendCompactSize(nil, v.n)))
+		require.Equal(t, len(v.encoded)/2, wire.CompactSizeLen(v.n))
+
+		var buf bytes.Buffer
+		require.NoError(t, wire.WriteCompactSize(&buf, v.n))
+		require.Equal(t, v.encoded, hex.EncodeToString(buf.Bytes()))
+
+		n, err := wire.ReadCompactSize(&buf)
+		require.NoError(t, err)
+		require.Equal(t, v.n, n)
+		require.Zero(t, buf.Len())
+	}
+}
+
+func TestReadCompactSizeErrors(t *testing.T) {
+	for _, h := range []string{"fd0000", "fdfc00", "feffff0000", "ffffffffff00000000", "ff0000000000000000"} {
+		data, _ := hex.DecodeString(h)
+		_, err := wire.ReadCompactSize(bytes.NewReader(data))
+		require.ErrorIs(t, err, wire.ErrNonCanonical, h)
+	}
+
+	for _, h := range []string{"fd", "fd00", "fe000001", "ff00000000010000"} {
+		data, _ := hex.DecodeString(h)
+		_, err := wire.ReadCompactSize(bytes.NewReader(data))
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF, h)
+	}
+
+	_, err := wire.ReadCompactSize(bytes.NewReader(nil))
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func FuzzReadCompactSize(f *testing.F) {
+	for _, v := range compactSizeVectors {
+		data, _ := hex.DecodeString(v.encoded)
+		f.Add(data)
+	}
+	f.Add([]byte{0xfd, 0x00, 0x00})
+
+	// a decoded value has a single encoding, the bytes it was read from
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+		n, err := wire.ReadCompactSize(r)
+		if err != nil {
+			return
+		}
+
+		read := len(data) - r.Len()
+		require.Equal(t, data[:read], wire.AppendCompactSize(nil, n))
+		require.Equal(t, read, wire.CompactSizeLen(n))
+	})
+}
@@ -0,0 +1,69 @@
+package wire
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// HashSize is the size of a double SHA256 hash
+const HashSize = 32
+
+var ErrInvalidHash = errors.New("invalid hash")
+
+// Hash is a transaction or block hash in internal byte order, it is
+// displayed and parsed reversed as Bitcoin Core does
+type Hash [HashSize]byte
+
+// ParseHash parses the reversed hex representation of a hash
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	if len(s) != 2*HashSize {
+		return h, fmt.Errorf("%w: %d characters", ErrInvalidHash, len(s))
+	}
+
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, fmt.Errorf("%w: %w", ErrInvalidHash, err)
+	}
+
+	return h.reversed(), nil
+}
+
+func (h Hash) String() string {
+	r := h.reversed()
+	return hex.EncodeToString(r[:])
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+
+	*h = parsed
+	return nil
+}
+
+func (h Hash) reversed() Hash {
+	for i := 0; i < HashSize/2; i++ {
+		h[i], h[HashSize-1-i] = h[HashSize-1-i], h[i]
+	}
+
+	return h
+}
+
+func ReadHash(r io.Reader) (Hash, error) {
+	var h Hash
+	_, err := io.ReadFull(r, h[:])
+	return h, err
+}
+
+func WriteHash(w io.Writer, h Hash) error {
+	_, err := w.Write(h[:])
+	return err
+}
@@ -0,0 +1,41 @@
+package wire_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	// mainnet genesis block, its hash ends with the zeros of the work
+	displayed := "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+	h, err := wire.ParseHash(displayed)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x6f), h[0])
+	require.Equal(t, byte(0x00), h[31])
+	require.Equal(t, displayed, h.String())
+
+	var buf bytes.Buffer
+	require.NoError(t, wire.WriteHash(&buf, h))
+	require.Equal(t, h[:], buf.Bytes())
+
+	read, err := wire.ReadHash(&buf)
+	require.NoError(t, err)
+	require.Equal(t, h, read)
+
+	encoded, err := json.Marshal(map[string]wire.Hash{"hash": h})
+	require.NoError(t, err)
+	require.Equal(t, `{"hash":"`+displayed+`"}`, string(encoded))
+
+	var decoded map[string]wire.Hash
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, h, decoded["hash"])
+
+	for _, s := range []string{"", displayed[2:], displayed + "00", "zz" + displayed[2:]} {
+		_, err := wire.ParseHash(s)
+		require.ErrorIs(t, err, wire.ErrInvalidHash, s)
+	}
+}
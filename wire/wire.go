@@ -0,0 +1,153 @@
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Primitive encodings of the bitcoin serialization format. Integers are
+// little endian, lengths are CompactSize varints and hashes are stored
+// in internal byte order. Readers return io.EOF only when nothing was
+// read and io.ErrUnexpectedEOF on a truncated value
+
+// MaxSize is the largest length Bitcoin Core deserializes, the
+// MAX_SIZE of its serialize.h
+const MaxSize = 0x02000000
+
+// readChunkSize bounds what is allocated ahead of the data being there
+const readChunkSize = 1 << 20
+
+var (
+	ErrNonCanonical = errors.New("non-canonical compact size")
+	ErrTooLarge     = errors.New("length exceeds maximum")
+)
+
+func ReadUint8(r io.Reader) (uint8, error) {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return buf[0], nil
+}
+
+func ReadUint16(r io.Reader) (uint16, error) {
+	var buf [2]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint16(buf[:]), nil
+}
+
+func ReadUint32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+func ReadUint64(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+func ReadInt32(r io.Reader) (int32, error) {
+	v, err := ReadUint32(r)
+	return int32(v), err
+}
+
+func ReadInt64(r io.Reader) (int64, error) {
+	v, err := ReadUint64(r)
+	return int64(v), err
+}
+
+func WriteUint8(w io.Writer, v uint8) error {
+	_, err := w.Write([]byte{v})
+	return err
+}
+
+func WriteUint16(w io.Writer, v uint16) error {
+	_, err := w.Write(binary.LittleEndian.AppendUint16(nil, v))
+	return err
+}
+
+func WriteUint32(w io.Writer, v uint32) error {
+	_, err := w.Write(binary.LittleEndian.AppendUint32(nil, v))
+	return err
+}
+
+func WriteUint64(w io.Writer, v uint64) error {
+	_, err := w.Write(binary.LittleEndian.AppendUint64(nil, v))
+	return err
+}
+
+func WriteInt32(w io.Writer, v int32) error {
+	return WriteUint32(w, uint32(v))
+}
+
+func WriteInt64(w io.Writer, v int64) error {
+	return WriteUint64(w, uint64(v))
+}
+
+// ReadVarBytes reads a CompactSize length followed by that many bytes,
+// lengths above maxSize are rejected before anything is allocated
+func ReadVarBytes(r io.Reader, maxSize uint64) ([]byte, error) {
+	n, err := ReadCompactSize(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if n > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes, at most %d", ErrTooLarge, n, maxSize)
+	}
+
+	// grow with the data so a forged length does not allocate it all
+	b := make([]byte, 0, min(n, readChunkSize))
+	for uint64(len(b)) < n {
+		chunk := min(n-uint64(len(b)), readChunkSize)
+		b = append(b, make([]byte, chunk)...)
+		if _, err := io.ReadFull(r, b[uint64(len(b))-chunk:]); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+	}
+
+	return b, nil
+}
+
+// ReadVarString reads a string serialized as var bytes
+func ReadVarString(r io.Reader, maxSize uint64) (string, error) {
+	b, err := ReadVarBytes(r, maxSize)
+	return string(b), err
+}
+
+func WriteVarBytes(w io.Writer, b []byte) error {
+	if err := WriteCompactSize(w, uint64(len(b))); err != nil {
+		return err
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
+func WriteVarString(w io.Writer, s string) error {
+	return WriteVarBytes(w, []byte(s))
+}
+
+// unexpectedEOF reports a clean EOF met after the start of a value as a
+// truncation
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,113 @@
+package wire_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegers(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, wire.WriteUint8(&buf, 0xab))
+	require.NoError(t, wire.WriteUint16(&buf, 0x0102))
+	require.NoError(t, wire.WriteUint32(&buf, 0x01020304))
+	require.NoError(t, wire.WriteUint64(&buf, 0x0102030405060708))
+	require.NoError(t, wire.WriteInt32(&buf, -2))
+	require.NoError(t, wire.WriteInt64(&buf, math.MinInt64))
+	require.Equal(t, "ab"+"0201"+"04030201"+"0807060504030201"+"feffffff"+"0000000000000080", hex.EncodeToString(buf.Bytes()))
+
+	u8, err := wire.ReadUint8(&buf)
+	require.NoError(t, err)
+	require.Equal(t, uint8(0xab), u8)
+
+	u16, err := wire.ReadUint16(&buf)
+	require.NoError(t, err)
+	require.Equal(t, uint16(0x0102), u16)
+
+	u32, err := wire.ReadUint32(&buf)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x01020304), u32)
+
+	u64, err := wire.ReadUint64(&buf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x0102030405060708), u64)
+
+	i32, err := wire.ReadInt32(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int32(-2), i32)
+
+	i64, err := wire.ReadInt64(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(math.MinInt64), i64)
+
+	_, err = wire.ReadUint32(&buf)
+	require.ErrorIs(t, err, io.EOF)
+	_, err = wire.ReadUint32(bytes.NewReader([]byte{1, 2}))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestVarBytes(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, wire.WriteVarBytes(&buf, []byte{0xde, 0xad}))
+	require.NoError(t, wire.WriteVarString(&buf, "/Satoshi:27.0.0/"))
+	require.NoError(t, wire.WriteVarBytes(&buf, nil))
+	require.Equal(t, "02dead"+"102f5361746f7368693a32372e302e302f"+"00", hex.EncodeToString(buf.Bytes()))
+
+	b, err := wire.ReadVarBytes(&buf, 2)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xde, 0xad}, b)
+
+	s, err := wire.ReadVarString(&buf, 256)
+	require.NoError(t, err)
+	require.Equal(t, "/Satoshi:27.0.0/", s)
+
+	b, err = wire.ReadVarBytes(&buf, 0)
+	require.NoError(t, err)
+	require.Empty(t, b)
+
+	_, err = wire.ReadVarBytes(bytes.NewReader([]byte{0x03, 1, 2, 3}), 2)
+	require.ErrorIs(t, err, wire.ErrTooLarge)
+	_, err = wire.ReadVarBytes(bytes.NewReader([]byte{0x03, 1, 2}), 3)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, err = wire.ReadVarBytes(bytes.NewReader([]byte{0x03}), 3)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, err = wire.ReadVarBytes(bytes.NewReader([]byte{0xfd, 0x01, 0x00}), 3)
+	require.ErrorIs(t, err, wire.ErrNonCanonical)
+
+	// a large declared length fails on the missing data
+	forged := wire.AppendCompactSize(nil, wire.MaxSize)
+	_, err = wire.ReadVarBytes(bytes.NewReader(append(forged, 1, 2, 3)), wire.MaxSize)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	large := []byte(strings.Repeat("x", 3<<20+5))
+	buf.Reset()
+	require.NoError(t, wire.WriteVarBytes(&buf, large))
+	b, err = wire.ReadVarBytes(&buf, wire.MaxSize)
+	require.NoError(t, err)
+	require.Equal(t, large, b)
+}
+
+func FuzzVarBytes(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x02, 0xde, 0xad})
+	f.Add([]byte{0xfd, 0x00, 0x01})
+
+	// whatever is read is written back to the same bytes
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+		b, err := wire.ReadVarBytes(r, 1<<16)
+		if err != nil {
+			return
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, wire.WriteVarBytes(&buf, b))
+		require.Equal(t, data[:len(data)-r.Len()], buf.Bytes())
+	})
+}